@@ -0,0 +1,158 @@
+package cross_bls
+
+// PublicKeyAggregator sums public keys one at a time. Public keys are removed from the sum
+// by subtracting them, and aggregators of public keys are merged into each other across backends.
+// Aggregators are not safe for concurrent use.
+type PublicKeyAggregator interface {
+	// Add adds a public key to the sum.
+	Add(publicKey PublicKey) error
+	// Remove subtracts a previously added public key from the sum.
+	Remove(publicKey PublicKey) error
+	// Merge adds the sum of other aggregator to the sum.
+	Merge(other PublicKeyAggregator) error
+	// Result returns the sum. Result of an empty aggregator is the point at infinity.
+	Result() PublicKey
+}
+
+// SignatureAggregator sums signatures one at a time like PublicKeyAggregator.
+type SignatureAggregator interface {
+	// Add adds a signature to the sum.
+	Add(signature Signature) error
+	// Remove subtracts a previously added signature from the sum.
+	Remove(signature Signature) error
+	// Merge adds the sum of other aggregator to the sum.
+	Merge(other SignatureAggregator) error
+	// Result returns the sum. Result of an empty aggregator is the point at infinity.
+	Result() Signature
+}
+
+// NewPublicKeyAggregator returns an empty aggregator of public keys.
+func NewPublicKeyAggregator() PublicKeyAggregator {
+	return currentSuite().NewPublicKeyAggregator()
+}
+
+// NewSignatureAggregator returns an empty aggregator of signatures.
+func NewSignatureAggregator() SignatureAggregator {
+	return currentSuite().NewSignatureAggregator()
+}
+
+func (suite *Suite) NewPublicKeyAggregator() PublicKeyAggregator {
+	return suite.backend.NewPublicKeyAggregator()
+}
+
+func (suite *Suite) NewSignatureAggregator() SignatureAggregator {
+	return suite.backend.NewSignatureAggregator()
+}
+
+// pointSum is a running sum of points of type P in the group of public keys or signatures.
+// Libraries implement it for G1 and G2, and it is shared by both variants.
+type pointSum[P any] interface {
+	add(p P)
+	sub(p P)
+	// result returns a copy of the sum.
+	result() P
+}
+
+// aggregatePoints returns the sum of points.
+func aggregatePoints[P any](sum pointSum[P], points []P) P {
+	for i := 0; i < len(points); i++ {
+		sum.add(points[i])
+	}
+	return sum.result()
+}
+
+// pointAggregator sums values of type V, public keys or signatures, over a running sum of
+// their points. from returns the point of a value and to returns the sum as a value.
+type pointAggregator[V interface{ ToBytes() []byte }, P any] struct {
+	sum  pointSum[P]
+	from func(value V) (P, error)
+	to   func(sum P) V
+}
+
+func (aggregator *pointAggregator[V, P]) Add(value V) error {
+	p, err := aggregator.from(value)
+	if err != nil {
+		return err
+	}
+	aggregator.sum.add(p)
+	return nil
+}
+
+func (aggregator *pointAggregator[V, P]) Remove(value V) error {
+	p, err := aggregator.from(value)
+	if err != nil {
+		return err
+	}
+	aggregator.sum.sub(p)
+	return nil
+}
+
+func (aggregator *pointAggregator[V, P]) Result() V {
+	return aggregator.to(aggregator.sum.result())
+}
+
+// merge adds the result of another aggregator to the sum. Sums at infinity are skipped
+// since they cannot be carried between backends through their compressed encodings.
+func (aggregator *pointAggregator[V, P]) merge(result V) error {
+	if any(result) == nil {
+		return errUnaggregatable
+	}
+	if compressed := result.ToBytes(); len(compressed) != 0 && compressed[0]&0x40 != 0 {
+		return nil
+	}
+	return aggregator.Add(result)
+}
+
+type publicKeyAggregator[P any] struct {
+	pointAggregator[PublicKey, P]
+}
+
+func (aggregator *publicKeyAggregator[P]) Merge(other PublicKeyAggregator) error {
+	if other == nil {
+		return errUnaggregatable
+	}
+	return aggregator.merge(other.Result())
+}
+
+type signatureAggregator[P any] struct {
+	pointAggregator[Signature, P]
+}
+
+func (aggregator *signatureAggregator[P]) Merge(other SignatureAggregator) error {
+	if other == nil {
+		return errUnaggregatable
+	}
+	return aggregator.merge(other.Result())
+}
+
+// newPublicKeyAggregator returns an aggregator of public keys of type T of backend.
+func newPublicKeyAggregator[T PublicKey, P any](backend Backend, sum pointSum[P], point func(T) P, to func(P) T) PublicKeyAggregator {
+	return &publicKeyAggregator[P]{pointAggregator[PublicKey, P]{
+		sum:  sum,
+		from: func(publicKey PublicKey) (P, error) { return aggregatedPoint(publicKey, backend, point) },
+		to:   func(sum P) PublicKey { return to(sum) },
+	}}
+}
+
+// newSignatureAggregator returns an aggregator of signatures of type T of backend.
+func newSignatureAggregator[T Signature, P any](backend Backend, sum pointSum[P], point func(T) P, to func(P) T) SignatureAggregator {
+	return &signatureAggregator[P]{pointAggregator[Signature, P]{
+		sum:  sum,
+		from: func(signature Signature) (P, error) { return aggregatedPoint(signature, backend, point) },
+		to:   func(sum P) Signature { return to(sum) },
+	}}
+}
+
+// aggregatedPoint returns the point of value that is added to an aggregator, converting
+// value to the type T of backend.
+func aggregatedPoint[T, P any](value interface{}, backend Backend, point func(T) P) (P, error) {
+	var zero P
+	if value == nil {
+		return zero, errUnaggregatable
+	}
+	v, err := convertTo[T](value, backend)
+	if err != nil {
+		return zero, err
+	}
+	return point(v), nil
+}
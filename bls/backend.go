@@ -0,0 +1,126 @@
+package cross_bls
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+	"sync"
+)
+
+// ErrUnknownBackend is returned when no backend is registered with the requested name.
+var ErrUnknownBackend = errors.New("unknown backend")
+
+// Backend is an implementation of BLS signatures over BLS12-381 curve.
+// Backends are registered by name and can be selected with UseBackend.
+type Backend interface {
+	Name() string
+	Variant() Variant
+	RandSecretKey() SecretKey
+	SecretKeyFromBytes(in []byte) (SecretKey, error)
+	PublicKeyFromBytes(compressed []byte) (PublicKey, error)
+	SignatureFromBytes(compressed []byte) (Signature, error)
+	PublicKeyFromUncompressed(uncompressed []byte) (PublicKey, error)
+	SignatureFromUncompressed(uncompressed []byte) (Signature, error)
+	AggregatePublicKeys(publicKeys []PublicKey) (PublicKey, error)
+	AggregateSignatures(signatures []Signature) (Signature, error)
+	NewPublicKeyAggregator() PublicKeyAggregator
+	NewSignatureAggregator() SignatureAggregator
+	Sign(secretKey SecretKey, message, dst []byte) Signature
+	Verify(signature Signature, publicKey PublicKey, message, dst []byte, options ValidationOptions) bool
+	FastAggregateVerify(signature Signature, publicKeys []PublicKey, message, dst []byte, options ValidationOptions) bool
+	AggregateVerify(signature Signature, publicKeys []PublicKey, messages [][]byte, dst []byte, options ValidationOptions) bool
+	VerifyMultipleSignatures(sets []SignatureSet, dst []byte, options ValidationOptions) (bool, error)
+}
+
+// initializer is implemented by backends that need a one time setup before use.
+type initializer interface {
+	initialize()
+}
+
+// uncheckedDecoder is implemented by backends that decode compressed and uncompressed public keys
+// and signatures without checking the subgroup where the library allows, see ValidationOptions.SkipDecodeSubgroupCheck.
+type uncheckedDecoder interface {
+	publicKeyFromBytesUnchecked(encoded []byte) (PublicKey, error)
+	signatureFromBytesUnchecked(encoded []byte) (Signature, error)
+}
+
+// pointMultiplier is implemented by backends that multiply public keys and signatures by scalars,
+// which threshold recovery uses to interpolate them with the backend's own arithmetic.
+type pointMultiplier interface {
+	mulPublicKey(publicKey PublicKey, scalar *big.Int) (PublicKey, error)
+	mulSignature(signature Signature, scalar *big.Int) (Signature, error)
+}
+
+// messageHasher is implemented by backends that hash messages to the signature group the way they sign,
+// which is G2 for MinPublicKeySize and G1 for MinSignatureSize. The hash is returned as a signature.
+type messageHasher interface {
+	hashToSignatureGroup(message, dst []byte) (Signature, error)
+}
+
+var backendsMu sync.RWMutex
+var backends = make(map[string]Backend)
+
+// Register makes a backend available by its name.
+// It panics if backend is nil or a backend with the same name is already registered.
+func Register(backend Backend) {
+	if backend == nil {
+		panic("bls: register nil backend")
+	}
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+	name := backend.Name()
+	if _, ok := backends[name]; ok {
+		panic("bls: register called twice for backend " + name)
+	}
+	backends[name] = backend
+}
+
+// Lookup returns the registered backend with the given name.
+func Lookup(name string) (Backend, error) {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+	backend, ok := backends[name]
+	if !ok {
+		return nil, ErrUnknownBackend
+	}
+	return backend, nil
+}
+
+// Backends returns sorted names of registered backends.
+func Backends() []string {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// unregister removes a registered backend, it is only used by tests.
+func unregister(name string) {
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+	delete(backends, name)
+}
+
+// UseBackend selects a registered backend for package level functions, which run the proof of
+// possession ciphersuite of the backend's variant.
+func UseBackend(name string) error {
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+	backend, ok := backends[name]
+	if !ok {
+		return ErrUnknownBackend
+	}
+	library = name
+	defaultSuite.Store(NewSuite(backend, PoP.DST(backend.Variant()), defaultValidationOptions))
+	return nil
+}
+
+func initBackend(backend Backend) {
+	if b, ok := backend.(initializer); ok {
+		b.initialize()
+	}
+}
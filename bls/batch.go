@@ -0,0 +1,51 @@
+package cross_bls
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+)
+
+// SignatureSet is an independent signature with its signer and message.
+type SignatureSet struct {
+	PublicKey PublicKey
+	Message   []byte
+	Signature Signature
+}
+
+// VerifyMultipleSignatures verifies independent signature sets at once.
+// Sets are combined with random 64 bit scalars and checked with a single multi pairing.
+func VerifyMultipleSignatures(sets []SignatureSet) (bool, error) {
+	return currentSuite().VerifyMultipleSignatures(sets)
+}
+
+func (suite *Suite) VerifyMultipleSignatures(sets []SignatureSet) (bool, error) {
+	if len(sets) == 0 {
+		return false, errEmptySignatureSets
+	}
+	for _, set := range sets {
+		if set.PublicKey == nil || set.Signature == nil {
+			return false, errInvalidSignatureSet
+		}
+	}
+	if suite.ciphersuite == Aug {
+		_sets := make([]SignatureSet, len(sets))
+		for i, set := range sets {
+			_sets[i] = SignatureSet{set.PublicKey, augment(set.PublicKey, set.Message), set.Signature}
+		}
+		sets = _sets
+	}
+	return suite.backend.VerifyMultipleSignatures(sets, suite.dst, suite.options)
+}
+
+// randBatchScalar returns a random non zero 64 bit scalar in big endian form.
+func randBatchScalar() ([]byte, error) {
+	r := make([]byte, 8)
+	for {
+		if _, err := rand.Read(r); err != nil {
+			return nil, err
+		}
+		if binary.BigEndian.Uint64(r) != 0 {
+			return r, nil
+		}
+	}
+}
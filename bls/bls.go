@@ -1,13 +1,12 @@
 package cross_bls
 
-import (
-	"bytes"
-)
-
 const (
 	SignatureSize = 96
 	PublicKeySize = 48
 	SecretKeySize = 32
+
+	UncompressedSignatureSize = 192
+	UncompressedPublicKeySize = 96
 )
 
 var dst = []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_")
@@ -22,12 +21,16 @@ type SecretKey interface {
 type PublicKey interface {
 	FromBytes(compressed []byte) (PublicKey, error)
 	ToBytes() []byte
+	FromUncompressed(uncompressed []byte) (PublicKey, error)
+	ToUncompressed() []byte
 	Equal(other PublicKey) bool
 }
 
 type Signature interface {
 	FromBytes(compressed []byte) (Signature, error)
 	ToBytes() []byte
+	FromUncompressed(uncompressed []byte) (Signature, error)
+	ToUncompressed() []byte
 	Equal(other Signature) bool
 	Verify(publicKey PublicKey, message []byte) bool
 	FastAggregateVerify(publicKeys []PublicKey, message []byte) bool
@@ -41,117 +44,49 @@ var zeroSignature = make([]byte, SignatureSize)
 var infiniteSignature []byte
 
 func RandSecretKey() SecretKey {
-	var secretKey SecretKey
-	switch library {
-	case libHerumi:
-		secretKey = randHerumiSecretKey()
-	case libBLST:
-		secretKey = randBLSTSecretKey()
-	case libKilic:
-		secretKey = randKilicSecretKey()
-	}
-	return secretKey
+	return currentSuite().RandSecretKey()
 }
 
 func SecretKeyFromBytes(_secretKey []byte) (SecretKey, error) {
-	if len(_secretKey) != SecretKeySize {
-		return nil, errSecretKeySize
-	}
-	if bytes.Equal(zeroSecretKey, _secretKey) {
-		return nil, errZeroSecretKey
-	}
-	var secretKey SecretKey
-	var err error
-	switch library {
-	case libHerumi:
-		secretKey, err = herumiSecretKeyFromBytes(_secretKey)
-	case libBLST:
-		secretKey, err = blstSecretKeyFromBytes(_secretKey)
-	case libKilic:
-		secretKey, err = kilicSecretKeyFromBytes(_secretKey)
-	}
-
-	if err != nil {
-		return nil, err
-	}
-	return secretKey, nil
+	return currentSuite().SecretKeyFromBytes(_secretKey)
 }
 
 func PublicKeyFromBytes(compressed []byte) (PublicKey, error) {
-	if len(compressed) != PublicKeySize {
-		return nil, errPublicKeySize
-	}
-	if bytes.Equal(zeroPublicKey, compressed) {
-		return nil, errZeroPublicKey
-	}
-	if bytes.Equal(infinitePublicKey, compressed) {
-		return nil, errInfinitePublicKey
-	}
-
-	var publicKey PublicKey
-	var err error
-	switch library {
-	case libHerumi:
-		publicKey, err = new(HerumiPublicKey).FromBytes(compressed)
-	case libBLST:
-		publicKey, err = new(BLSTPublicKey).FromBytes(compressed)
-	case libKilic:
-		publicKey, err = new(KilicPublicKey).FromBytes(compressed)
-	}
-	if err != nil {
-		return nil, err
-	}
-	return publicKey, nil
+	return currentSuite().PublicKeyFromBytes(compressed)
 }
 
 func SignatureFromBytes(compressed []byte) (Signature, error) {
-	if len(compressed) != SignatureSize {
-		return nil, errSignatureSize
-	}
-	if bytes.Equal(zeroSignature, compressed) {
-		return nil, errZeroSignature
-	}
-	if bytes.Equal(infiniteSignature, compressed) {
-		return nil, errInfiniteSignature
-	}
-	var signature Signature
-	var err error
-	switch library {
-	case libHerumi:
-		signature, err = new(HerumiSignature).FromBytes(compressed)
-	case libBLST:
-		signature, err = new(BLSTSignature).FromBytes(compressed)
-	case libKilic:
-		signature, err = new(KilicSignature).FromBytes(compressed)
-	}
-	if err != nil {
-		return nil, err
-	}
-	return signature, nil
+	return currentSuite().SignatureFromBytes(compressed)
+}
+
+func PublicKeyFromUncompressed(uncompressed []byte) (PublicKey, error) {
+	return currentSuite().PublicKeyFromUncompressed(uncompressed)
+}
+
+func SignatureFromUncompressed(uncompressed []byte) (Signature, error) {
+	return currentSuite().SignatureFromUncompressed(uncompressed)
+}
+
+func AggregatePublicKeys(publicKeys []PublicKey) (PublicKey, error) {
+	return currentSuite().AggregatePublicKeys(publicKeys)
+}
+
+func AggregateSignatures(signatures []Signature) (Signature, error) {
+	return currentSuite().AggregateSignatures(signatures)
 }
 
+// AggreagatePublicKeys returns nil if public keys cannot be aggregated.
+//
+// Deprecated: Use AggregatePublicKeys.
 func AggreagatePublicKeys(publicKeys []PublicKey) PublicKey {
-	var publicKey PublicKey
-	switch library {
-	case libHerumi:
-		publicKey = herumiAggregatePublicKey(publicKeys)
-	case libBLST:
-		publicKey = blstAggregatePublicKey(publicKeys)
-	case libKilic:
-		publicKey = kilicAggregatePublicKey(publicKeys, nil)
-	}
+	publicKey, _ := AggregatePublicKeys(publicKeys)
 	return publicKey
 }
 
+// AggreagateSignatures returns nil if signatures cannot be aggregated.
+//
+// Deprecated: Use AggregateSignatures.
 func AggreagateSignatures(signatures []Signature) Signature {
-	var signature Signature
-	switch library {
-	case libHerumi:
-		signature = herumiAggregateSignature(signatures)
-	case libBLST:
-		signature = blstAggregateSignature(signatures)
-	case libKilic:
-		signature = kilicAggregateSignature(signatures, nil)
-	}
+	signature, _ := AggregateSignatures(signatures)
 	return signature
 }
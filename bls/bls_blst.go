@@ -2,7 +2,8 @@ package cross_bls
 
 import (
 	"crypto/rand"
-	"errors"
+	"math/big"
+	"sync"
 
 	blst "github.com/supranational/blst/bindings/go"
 )
@@ -23,8 +24,234 @@ type BLSTSignature struct {
 	p *blst.P2Affine
 }
 
-var blstOptionCheckSignatureSubgroupInVerification = false
-var blstOptionValidatePublicKeyInVerification = false
+type blstBackend struct{}
+
+func (blstBackend) Name() string {
+	return libBLST
+}
+
+func (blstBackend) Variant() Variant {
+	return MinPublicKeySize
+}
+
+func (blstBackend) initialize() {
+	initBLST()
+}
+
+func (blstBackend) RandSecretKey() SecretKey {
+	return randBLSTSecretKey()
+}
+
+func (blstBackend) SecretKeyFromBytes(in []byte) (SecretKey, error) {
+	return blstSecretKeyFromBytes(in)
+}
+
+func (blstBackend) PublicKeyFromBytes(compressed []byte) (PublicKey, error) {
+	return new(BLSTPublicKey).FromBytes(compressed)
+}
+
+func (blstBackend) SignatureFromBytes(compressed []byte) (Signature, error) {
+	return new(BLSTSignature).FromBytes(compressed)
+}
+
+func (blstBackend) PublicKeyFromUncompressed(uncompressed []byte) (PublicKey, error) {
+	return new(BLSTPublicKey).FromUncompressed(uncompressed)
+}
+
+func (blstBackend) SignatureFromUncompressed(uncompressed []byte) (Signature, error) {
+	return new(BLSTSignature).FromUncompressed(uncompressed)
+}
+
+func (blstBackend) publicKeyFromBytesUnchecked(encoded []byte) (PublicKey, error) {
+	if len(encoded) != PublicKeySize && len(encoded) != UncompressedPublicKeySize {
+		return nil, errPublicKeySize
+	}
+	p := blstDecode[blstPublicKey](encoded, PublicKeySize)
+	if p == nil {
+		return nil, publicKeyError(encoded, false, nil)
+	}
+	return &BLSTPublicKey{p}, nil
+}
+
+func (blstBackend) signatureFromBytesUnchecked(encoded []byte) (Signature, error) {
+	if len(encoded) != SignatureSize && len(encoded) != UncompressedSignatureSize {
+		return nil, errSignatureSize
+	}
+	p := blstDecode[blstSignature](encoded, SignatureSize)
+	if p == nil {
+		return nil, signatureError(encoded, true, nil)
+	}
+	return &BLSTSignature{p}, nil
+}
+
+func (blstBackend) AggregatePublicKeys(publicKeys []PublicKey) (PublicKey, error) {
+	return blstAggregatePublicKey(publicKeys)
+}
+
+func (blstBackend) AggregateSignatures(signatures []Signature) (Signature, error) {
+	return blstAggregateSignature(signatures)
+}
+
+func (blstBackend) mulPublicKey(publicKey PublicKey, scalar *big.Int) (PublicKey, error) {
+	_publicKey, err := toBLSTPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	var p blst.P1
+	p.FromAffine(_publicKey.p)
+	return &BLSTPublicKey{p.MultAssign(blstScalar(scalar)).ToAffine()}, nil
+}
+
+func (blstBackend) mulSignature(signature Signature, scalar *big.Int) (Signature, error) {
+	_signature, err := toBLSTSignature(signature)
+	if err != nil {
+		return nil, err
+	}
+	var p blst.P2
+	p.FromAffine(_signature.p)
+	return &BLSTSignature{p.MultAssign(blstScalar(scalar)).ToAffine()}, nil
+}
+
+func (blstBackend) NewPublicKeyAggregator() PublicKeyAggregator {
+	point := func(publicKey *BLSTPublicKey) *blstPublicKey { return publicKey.p }
+	to := func(sum *blstPublicKey) *BLSTPublicKey { return &BLSTPublicKey{sum} }
+	return newPublicKeyAggregator(blstBackend{}, newBLSTG1Sum(), point, to)
+}
+
+func (blstBackend) NewSignatureAggregator() SignatureAggregator {
+	point := func(signature *BLSTSignature) *blstSignature { return signature.p }
+	to := func(sum *blstSignature) *BLSTSignature { return &BLSTSignature{sum} }
+	return newSignatureAggregator(blstBackend{}, newBLSTG2Sum(), point, to)
+}
+
+func (blstBackend) Sign(secretKey SecretKey, message, dst []byte) Signature {
+	_secretKey, err := toBLSTSecretKey(secretKey)
+	if err != nil {
+		return nil
+	}
+	blstSignature := new(blstSignature).Sign(_secretKey.s, message, dst)
+	return &BLSTSignature{blstSignature}
+}
+
+func (blstBackend) hashToSignatureGroup(message, dst []byte) (Signature, error) {
+	return &BLSTSignature{blst.HashToG2(message, dst).ToAffine()}, nil
+}
+
+func (blstBackend) Verify(signature Signature, publicKey PublicKey, message, dst []byte, options ValidationOptions) bool {
+	_signature, err := toBLSTSignature(signature)
+	if err != nil {
+		return false
+	}
+	_publicKey, err := toBLSTPublicKey(publicKey)
+	if err != nil {
+		return false
+	}
+	return _signature.p.
+		Verify(
+			options.CheckSignatureSubgroup,
+			_publicKey.p,
+			options.ValidatePublicKey,
+			message,
+			dst,
+		)
+}
+
+func (blstBackend) FastAggregateVerify(signature Signature, publicKeys []PublicKey, message, dst []byte, options ValidationOptions) bool {
+	_signature, err := toBLSTSignature(signature)
+	if err != nil {
+		return false
+	}
+	blstPublicKeys, err := toBLSTPublicKeys(publicKeys)
+	if err != nil {
+		return false
+	}
+	if !blstValidatePublicKeys(blstPublicKeys, options) {
+		return false
+	}
+	return _signature.p.
+		FastAggregateVerify(
+			options.CheckSignatureSubgroup,
+			blstPublicKeys,
+			message,
+			dst,
+		)
+}
+
+func (blstBackend) AggregateVerify(signature Signature, publicKeys []PublicKey, messages [][]byte, dst []byte, options ValidationOptions) bool {
+	size := len(publicKeys)
+	if size == 0 {
+		return false
+	}
+	if len(messages) != size {
+		return false
+	}
+	_signature, err := toBLSTSignature(signature)
+	if err != nil {
+		return false
+	}
+	blstPublicKeys, err := toBLSTPublicKeys(publicKeys)
+	if err != nil {
+		return false
+	}
+	return _signature.p.
+		AggregateVerify(
+			options.CheckSignatureSubgroup,
+			blstPublicKeys,
+			options.ValidatePublicKey,
+			messages,
+			dst,
+		)
+}
+
+func (blstBackend) VerifyMultipleSignatures(sets []SignatureSet, dst []byte, options ValidationOptions) (bool, error) {
+	size := len(sets)
+	blstSignatures := make([]*blstSignature, size)
+	blstPublicKeys := make([]*blstPublicKey, size)
+	messages := make([]blst.Message, size)
+	for i := 0; i < size; i++ {
+		signature, err := toBLSTSignature(sets[i].Signature)
+		if err != nil {
+			return false, err
+		}
+		publicKey, err := toBLSTPublicKey(sets[i].PublicKey)
+		if err != nil {
+			return false, err
+		}
+		blstSignatures[i] = signature.p
+		blstPublicKeys[i] = publicKey.p
+		messages[i] = sets[i].Message
+	}
+	// randFn is called concurrently by blst workers
+	var randErr error
+	var randMu sync.Mutex
+	randFn := func(s *blst.Scalar) {
+		var r [blst.BLST_SCALAR_BYTES]byte
+		_r, err := randBatchScalar()
+		if err != nil {
+			randMu.Lock()
+			randErr = err
+			randMu.Unlock()
+			return
+		}
+		copy(r[len(r)-len(_r):], _r)
+		s.FromBEndian(r[:])
+	}
+	ok := new(blstSignature).
+		MultipleAggregateVerify(
+			blstSignatures,
+			options.CheckSignatureSubgroup,
+			blstPublicKeys,
+			options.ValidatePublicKey,
+			messages,
+			dst,
+			randFn,
+			64,
+		)
+	if randErr != nil {
+		return false, randErr
+	}
+	return ok, nil
+}
 
 func randBLSTSecretKey() SecretKey {
 	var t [32]byte
@@ -40,13 +267,17 @@ func blstSecretKeyFromBytes(in []byte) (SecretKey, error) {
 	secretKey := new(blstSecretKey)
 	secretKey = secretKey.Deserialize(in)
 	if secretKey == nil {
-		return nil, errInvalidSecretKey
+		return nil, errSecretKeyNonCanonical
 	}
 	return &BLSTSecretKey{secretKey}, nil
 }
 
 func (secretKey *BLSTSecretKey) Equal(other SecretKey) bool {
-	return secretKey.s.Equals(other.(*BLSTSecretKey).s)
+	_other, err := toBLSTSecretKey(other)
+	if err != nil {
+		return false
+	}
+	return secretKey.s.Equals(_other.s)
 }
 
 func (secretKey *BLSTSecretKey) PublicKey() PublicKey {
@@ -54,8 +285,7 @@ func (secretKey *BLSTSecretKey) PublicKey() PublicKey {
 }
 
 func (secretKey *BLSTSecretKey) Sign(message []byte) Signature {
-	blstSignature := new(blstSignature).Sign(secretKey.s, message, dst)
-	return &BLSTSignature{blstSignature}
+	return blstBackend{}.Sign(secretKey, message, dst)
 }
 
 func (secretKey *BLSTSecretKey) ToBytes() []byte {
@@ -63,18 +293,14 @@ func (secretKey *BLSTSecretKey) ToBytes() []byte {
 }
 
 func (publicKey *BLSTPublicKey) FromBytes(compressed []byte) (PublicKey, error) {
-
 	if len(compressed) != PublicKeySize {
-		return nil, errors.New("invalid public key size")
+		return nil, errPublicKeySize
 	}
-	blstPublicKey := new(blstPublicKey).Uncompress(compressed)
-	if blstPublicKey == nil {
-		return nil, errors.New("cannot uncompress given public key in bytes")
+	p, err := blstValidate(blstDecode[blstPublicKey](compressed, PublicKeySize), compressed, false, publicKeyError)
+	if err != nil {
+		return nil, err
 	}
-	if !blstPublicKey.KeyValidate() {
-		return nil, errors.New("invalid BLS public key")
-	}
-	publicKey.p = blstPublicKey
+	publicKey.p = p
 	return publicKey, nil
 }
 
@@ -82,22 +308,39 @@ func (publicKey *BLSTPublicKey) ToBytes() []byte {
 	return publicKey.p.Compress()
 }
 
+func (publicKey *BLSTPublicKey) FromUncompressed(uncompressed []byte) (PublicKey, error) {
+	if err := checkUncompressedPublicKey(uncompressed, UncompressedPublicKeySize); err != nil {
+		return nil, err
+	}
+	p, err := blstValidate(blstDecode[blstPublicKey](uncompressed, PublicKeySize), uncompressed, false, publicKeyError)
+	if err != nil {
+		return nil, err
+	}
+	publicKey.p = p
+	return publicKey, nil
+}
+
+func (publicKey *BLSTPublicKey) ToUncompressed() []byte {
+	return publicKey.p.Serialize()
+}
+
 func (publicKey *BLSTPublicKey) Equal(other PublicKey) bool {
-	return publicKey.p.Equals(other.(*BLSTPublicKey).p)
+	_other, err := toBLSTPublicKey(other)
+	if err != nil {
+		return false
+	}
+	return publicKey.p.Equals(_other.p)
 }
 
 func (signature *BLSTSignature) FromBytes(compressed []byte) (Signature, error) {
 	if len(compressed) != SignatureSize {
 		return nil, errSignatureSize
 	}
-	blstSignature := new(blstSignature).Uncompress(compressed)
-	if blstSignature == nil {
-		return nil, errInvalidSignature
-	}
-	if !blstSignature.KeyValidate() {
-		return nil, errInvalidSignature
+	p, err := blstValidate(blstDecode[blstSignature](compressed, SignatureSize), compressed, true, signatureError)
+	if err != nil {
+		return nil, err
 	}
-	signature.p = blstSignature
+	signature.p = p
 	return signature, nil
 }
 
@@ -105,75 +348,170 @@ func (signature *BLSTSignature) ToBytes() []byte {
 	return signature.p.Compress()
 }
 
+func (signature *BLSTSignature) FromUncompressed(uncompressed []byte) (Signature, error) {
+	if err := checkUncompressedSignature(uncompressed, UncompressedSignatureSize); err != nil {
+		return nil, err
+	}
+	p, err := blstValidate(blstDecode[blstSignature](uncompressed, SignatureSize), uncompressed, true, signatureError)
+	if err != nil {
+		return nil, err
+	}
+	signature.p = p
+	return signature, nil
+}
+
+func (signature *BLSTSignature) ToUncompressed() []byte {
+	return signature.p.Serialize()
+}
+
 func (signature *BLSTSignature) Equal(other Signature) bool {
-	return signature.p.Equals(other.(*BLSTSignature).p)
+	_other, err := toBLSTSignature(other)
+	if err != nil {
+		return false
+	}
+	return signature.p.Equals(_other.p)
 }
 
 func (signature *BLSTSignature) Verify(publicKey PublicKey, message []byte) bool {
-	return signature.p.
-		Verify(
-			blstOptionCheckSignatureSubgroupInVerification,
-			publicKey.(*BLSTPublicKey).p,
-			blstOptionValidatePublicKeyInVerification,
-			message,
-			dst,
-		)
+	return blstBackend{}.Verify(signature, publicKey, message, dst, defaultValidationOptions)
 }
 
 func (signature *BLSTSignature) FastAggregateVerify(publicKeys []PublicKey, message []byte) bool {
-	blstPublicKeys := make([]*blstPublicKey, len(publicKeys))
-	for i := 0; i < len(publicKeys); i++ {
-		blstPublicKeys[i] = publicKeys[i].(*BLSTPublicKey).p
-	}
-	return signature.p.
-		FastAggregateVerify(
-			blstOptionCheckSignatureSubgroupInVerification,
-			blstPublicKeys,
-			message[:],
-			dst,
-		)
+	return blstBackend{}.FastAggregateVerify(signature, publicKeys, message, dst, defaultValidationOptions)
 }
 
 func (signature *BLSTSignature) AggregateVerify(publicKeys []PublicKey, messages [][]byte) bool {
-	size := len(publicKeys)
-	if size == 0 {
-		return false
+	return blstBackend{}.AggregateVerify(signature, publicKeys, messages, dst, defaultValidationOptions)
+}
+
+func blstAggregateSignature(signatures []Signature) (Signature, error) {
+	blstSignatures, err := convertPoints(signatures, blstBackend{}, func(signature *BLSTSignature) *blstSignature { return signature.p })
+	if err != nil {
+		return nil, err
 	}
-	if len(messages) != size {
-		return false
+	return &BLSTSignature{aggregatePoints(newBLSTG2Sum(), blstSignatures)}, nil
+}
+
+func blstAggregatePublicKey(publicKeys []PublicKey) (PublicKey, error) {
+	blstPublicKeys, err := toBLSTPublicKeys(publicKeys)
+	if err != nil {
+		return nil, err
 	}
-	blstPublicKeys := make([]*blstPublicKey, len(publicKeys))
-	for i := 0; i < size; i++ {
-		blstPublicKeys[i] = publicKeys[i].(*BLSTPublicKey).p
+	return &BLSTPublicKey{aggregatePoints(newBLSTG1Sum(), blstPublicKeys)}, nil
+}
+
+// blstAffine is the affine point type A of blst G1 and G2.
+type blstAffine[A any] interface {
+	*A
+	Uncompress(in []byte) *A
+	Deserialize(in []byte) *A
+	KeyValidate() bool
+}
+
+// blstDecode decodes a compressed point if encoded is compressedSize long and an uncompressed
+// point otherwise. It returns nil if blst rejects the encoding.
+func blstDecode[A any, P blstAffine[A]](encoded []byte, compressedSize int) *A {
+	if len(encoded) == compressedSize {
+		return P(new(A)).Uncompress(encoded)
 	}
-	return signature.p.
-		AggregateVerify(
-			blstOptionCheckSignatureSubgroupInVerification,
-			blstPublicKeys,
-			blstOptionValidatePublicKeyInVerification,
-			messages,
-			dst,
-		)
+	return P(new(A)).Deserialize(encoded)
 }
 
-func blstAggregateSignature(signatures []Signature) Signature {
-	size := len(signatures)
-	blstSignatures := make([]*blstSignature, size)
-	for i := 0; i < size; i++ {
-		blstSignatures[i] = signatures[i].(*BLSTSignature).p
+// blstValidate returns the decoded point p of encoded if it is a valid key, which is neither
+// the point at infinity nor out of the subgroup. fail returns the error of a rejected point.
+func blstValidate[A any, P blstAffine[A]](p *A, encoded []byte, onG2 bool, fail pointError) (*A, error) {
+	if p == nil || !P(p).KeyValidate() {
+		return nil, fail(encoded, onG2, blstReason(p))
 	}
-	aggregatedSignature := new(blst.P2Aggregate)
-	aggregatedSignature.Aggregate(blstSignatures, false)
-	return &BLSTSignature{aggregatedSignature.ToAffine()}
+	return p, nil
 }
 
-func blstAggregatePublicKey(publicKeys []PublicKey) PublicKey {
-	size := len(publicKeys)
-	blstPublicKeys := make([]*blstPublicKey, size)
-	for i := 0; i < size; i++ {
-		blstPublicKeys[i] = publicKeys[i].(*BLSTPublicKey).p
+// blstValidatePublicKeys validates public keys if options ask for it. blst aggregates public keys
+// before verification without validating them.
+func blstValidatePublicKeys[A any, P blstAffine[A]](publicKeys []*A, options ValidationOptions) bool {
+	for i := 0; i < len(publicKeys) && options.ValidatePublicKey; i++ {
+		if !P(publicKeys[i]).KeyValidate() {
+			return false
+		}
 	}
-	aggregatedPublicKey := new(blst.P1Aggregate)
-	aggregatedPublicKey.Aggregate(blstPublicKeys, false)
-	return &BLSTPublicKey{aggregatedPublicKey.ToAffine()}
+	return true
+}
+
+// blstG1Sum sums G1 points of public keys or of minimal size signatures.
+type blstG1Sum struct {
+	sum blst.P1
+}
+
+func newBLSTG1Sum() pointSum[*blst.P1Affine] {
+	return new(blstG1Sum)
+}
+
+func (sum *blstG1Sum) add(p *blst.P1Affine) {
+	sum.sum.AddAssign(p)
+}
+
+func (sum *blstG1Sum) sub(p *blst.P1Affine) {
+	sum.sum.SubAssign(p)
+}
+
+func (sum *blstG1Sum) result() *blst.P1Affine {
+	return sum.sum.ToAffine()
+}
+
+// blstG2Sum sums G2 points of signatures or of minimal signature size public keys.
+type blstG2Sum struct {
+	sum blst.P2
+}
+
+func newBLSTG2Sum() pointSum[*blst.P2Affine] {
+	return new(blstG2Sum)
+}
+
+func (sum *blstG2Sum) add(p *blst.P2Affine) {
+	sum.sum.AddAssign(p)
+}
+
+func (sum *blstG2Sum) sub(p *blst.P2Affine) {
+	sum.sum.SubAssign(p)
+}
+
+func (sum *blstG2Sum) result() *blst.P2Affine {
+	return sum.sum.ToAffine()
+}
+
+func toBLSTSecretKey(secretKey SecretKey) (*BLSTSecretKey, error) {
+	return convertTo[*BLSTSecretKey](secretKey, blstBackend{})
+}
+
+// blstScalar returns scalar modulo group order as a blst scalar.
+func blstScalar(scalar *big.Int) *blst.Scalar {
+	return new(blst.Scalar).FromBEndian(new(big.Int).Mod(scalar, groupOrder).FillBytes(make([]byte, 32)))
+}
+
+// blstReason returns ErrNotInSubgroup if blst decoded the point p on G1 or G2 but rejected it
+// out of subgroup. blst does not report other failures.
+func blstReason(p interface{}) error {
+	switch p := p.(type) {
+	case *blst.P1Affine:
+		if p != nil && !p.InG1() {
+			return ErrNotInSubgroup
+		}
+	case *blst.P2Affine:
+		if p != nil && !p.InG2() {
+			return ErrNotInSubgroup
+		}
+	}
+	return nil
+}
+
+func toBLSTPublicKey(publicKey PublicKey) (*BLSTPublicKey, error) {
+	return convertTo[*BLSTPublicKey](publicKey, blstBackend{})
+}
+
+func toBLSTPublicKeys(publicKeys []PublicKey) ([]*blstPublicKey, error) {
+	return convertPoints(publicKeys, blstBackend{}, func(publicKey *BLSTPublicKey) *blstPublicKey { return publicKey.p })
+}
+
+func toBLSTSignature(signature Signature) (*BLSTSignature, error) {
+	return convertTo[*BLSTSignature](signature, blstBackend{})
 }
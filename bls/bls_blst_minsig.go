@@ -0,0 +1,400 @@
+package cross_bls
+
+import (
+	"math/big"
+	"sync"
+
+	blst "github.com/supranational/blst/bindings/go"
+)
+
+type blstMinSigPublicKey = blst.P2Affine
+type blstMinSigSignature = blst.P1Affine
+
+type BLSTMinSigPublicKey struct {
+	p *blst.P2Affine
+}
+
+type BLSTMinSigSecretKey struct {
+	s *blst.SecretKey
+}
+
+type BLSTMinSigSignature struct {
+	p *blst.P1Affine
+}
+
+type blstMinSigBackend struct{}
+
+func (blstMinSigBackend) Name() string {
+	return libBLSTMinSig
+}
+
+func (blstMinSigBackend) Variant() Variant {
+	return MinSignatureSize
+}
+
+func (blstMinSigBackend) initialize() {
+	initBLST()
+}
+
+func (blstMinSigBackend) RandSecretKey() SecretKey {
+	return &BLSTMinSigSecretKey{randBLSTSecretKey().(*BLSTSecretKey).s}
+}
+
+func (blstMinSigBackend) SecretKeyFromBytes(in []byte) (SecretKey, error) {
+	secretKey, err := blstSecretKeyFromBytes(in)
+	if err != nil {
+		return nil, err
+	}
+	return &BLSTMinSigSecretKey{secretKey.(*BLSTSecretKey).s}, nil
+}
+
+func (blstMinSigBackend) PublicKeyFromBytes(compressed []byte) (PublicKey, error) {
+	return new(BLSTMinSigPublicKey).FromBytes(compressed)
+}
+
+func (blstMinSigBackend) SignatureFromBytes(compressed []byte) (Signature, error) {
+	return new(BLSTMinSigSignature).FromBytes(compressed)
+}
+
+func (blstMinSigBackend) PublicKeyFromUncompressed(uncompressed []byte) (PublicKey, error) {
+	return new(BLSTMinSigPublicKey).FromUncompressed(uncompressed)
+}
+
+func (blstMinSigBackend) SignatureFromUncompressed(uncompressed []byte) (Signature, error) {
+	return new(BLSTMinSigSignature).FromUncompressed(uncompressed)
+}
+
+func (blstMinSigBackend) publicKeyFromBytesUnchecked(encoded []byte) (PublicKey, error) {
+	if len(encoded) != MinSigPublicKeySize && len(encoded) != UncompressedSignatureSize {
+		return nil, errPublicKeySize
+	}
+	p := blstDecode[blstMinSigPublicKey](encoded, MinSigPublicKeySize)
+	if p == nil {
+		return nil, publicKeyError(encoded, true, nil)
+	}
+	return &BLSTMinSigPublicKey{p}, nil
+}
+
+func (blstMinSigBackend) signatureFromBytesUnchecked(encoded []byte) (Signature, error) {
+	if len(encoded) != MinSigSignatureSize && len(encoded) != UncompressedPublicKeySize {
+		return nil, errSignatureSize
+	}
+	p := blstDecode[blstMinSigSignature](encoded, MinSigSignatureSize)
+	if p == nil {
+		return nil, signatureError(encoded, false, nil)
+	}
+	return &BLSTMinSigSignature{p}, nil
+}
+
+func (blstMinSigBackend) AggregatePublicKeys(publicKeys []PublicKey) (PublicKey, error) {
+	return blstMinSigAggregatePublicKey(publicKeys)
+}
+
+func (blstMinSigBackend) AggregateSignatures(signatures []Signature) (Signature, error) {
+	return blstMinSigAggregateSignature(signatures)
+}
+
+func (blstMinSigBackend) mulPublicKey(publicKey PublicKey, scalar *big.Int) (PublicKey, error) {
+	_publicKey, err := toBLSTMinSigPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	var p blst.P2
+	p.FromAffine(_publicKey.p)
+	return &BLSTMinSigPublicKey{p.MultAssign(blstScalar(scalar)).ToAffine()}, nil
+}
+
+func (blstMinSigBackend) mulSignature(signature Signature, scalar *big.Int) (Signature, error) {
+	_signature, err := toBLSTMinSigSignature(signature)
+	if err != nil {
+		return nil, err
+	}
+	var p blst.P1
+	p.FromAffine(_signature.p)
+	return &BLSTMinSigSignature{p.MultAssign(blstScalar(scalar)).ToAffine()}, nil
+}
+
+func (blstMinSigBackend) NewPublicKeyAggregator() PublicKeyAggregator {
+	point := func(publicKey *BLSTMinSigPublicKey) *blstMinSigPublicKey { return publicKey.p }
+	to := func(sum *blstMinSigPublicKey) *BLSTMinSigPublicKey { return &BLSTMinSigPublicKey{sum} }
+	return newPublicKeyAggregator(blstMinSigBackend{}, newBLSTG2Sum(), point, to)
+}
+
+func (blstMinSigBackend) NewSignatureAggregator() SignatureAggregator {
+	point := func(signature *BLSTMinSigSignature) *blstMinSigSignature { return signature.p }
+	to := func(sum *blstMinSigSignature) *BLSTMinSigSignature { return &BLSTMinSigSignature{sum} }
+	return newSignatureAggregator(blstMinSigBackend{}, newBLSTG1Sum(), point, to)
+}
+
+func (blstMinSigBackend) Sign(secretKey SecretKey, message, dst []byte) Signature {
+	_secretKey, err := toBLSTMinSigSecretKey(secretKey)
+	if err != nil {
+		return nil
+	}
+	blstSignature := new(blstMinSigSignature).Sign(_secretKey.s, message, dst)
+	return &BLSTMinSigSignature{blstSignature}
+}
+
+func (blstMinSigBackend) hashToSignatureGroup(message, dst []byte) (Signature, error) {
+	return &BLSTMinSigSignature{blst.HashToG1(message, dst).ToAffine()}, nil
+}
+
+func (blstMinSigBackend) Verify(signature Signature, publicKey PublicKey, message, dst []byte, options ValidationOptions) bool {
+	_signature, err := toBLSTMinSigSignature(signature)
+	if err != nil {
+		return false
+	}
+	_publicKey, err := toBLSTMinSigPublicKey(publicKey)
+	if err != nil {
+		return false
+	}
+	return _signature.p.
+		Verify(
+			options.CheckSignatureSubgroup,
+			_publicKey.p,
+			options.ValidatePublicKey,
+			message,
+			dst,
+		)
+}
+
+func (blstMinSigBackend) FastAggregateVerify(signature Signature, publicKeys []PublicKey, message, dst []byte, options ValidationOptions) bool {
+	_signature, err := toBLSTMinSigSignature(signature)
+	if err != nil {
+		return false
+	}
+	blstPublicKeys, err := toBLSTMinSigPublicKeys(publicKeys)
+	if err != nil {
+		return false
+	}
+	if !blstValidatePublicKeys(blstPublicKeys, options) {
+		return false
+	}
+	return _signature.p.
+		FastAggregateVerify(
+			options.CheckSignatureSubgroup,
+			blstPublicKeys,
+			message,
+			dst,
+		)
+}
+
+func (blstMinSigBackend) AggregateVerify(signature Signature, publicKeys []PublicKey, messages [][]byte, dst []byte, options ValidationOptions) bool {
+	size := len(publicKeys)
+	if size == 0 {
+		return false
+	}
+	if len(messages) != size {
+		return false
+	}
+	_signature, err := toBLSTMinSigSignature(signature)
+	if err != nil {
+		return false
+	}
+	blstPublicKeys, err := toBLSTMinSigPublicKeys(publicKeys)
+	if err != nil {
+		return false
+	}
+	return _signature.p.
+		AggregateVerify(
+			options.CheckSignatureSubgroup,
+			blstPublicKeys,
+			options.ValidatePublicKey,
+			messages,
+			dst,
+		)
+}
+
+func (blstMinSigBackend) VerifyMultipleSignatures(sets []SignatureSet, dst []byte, options ValidationOptions) (bool, error) {
+	size := len(sets)
+	blstSignatures := make([]*blstMinSigSignature, size)
+	blstPublicKeys := make([]*blstMinSigPublicKey, size)
+	messages := make([]blst.Message, size)
+	for i := 0; i < size; i++ {
+		signature, err := toBLSTMinSigSignature(sets[i].Signature)
+		if err != nil {
+			return false, err
+		}
+		publicKey, err := toBLSTMinSigPublicKey(sets[i].PublicKey)
+		if err != nil {
+			return false, err
+		}
+		blstSignatures[i] = signature.p
+		blstPublicKeys[i] = publicKey.p
+		messages[i] = sets[i].Message
+	}
+	// randFn is called concurrently by blst workers
+	var randErr error
+	var randMu sync.Mutex
+	randFn := func(s *blst.Scalar) {
+		var r [blst.BLST_SCALAR_BYTES]byte
+		_r, err := randBatchScalar()
+		if err != nil {
+			randMu.Lock()
+			randErr = err
+			randMu.Unlock()
+			return
+		}
+		copy(r[len(r)-len(_r):], _r)
+		s.FromBEndian(r[:])
+	}
+	ok := new(blstMinSigSignature).
+		MultipleAggregateVerify(
+			blstSignatures,
+			options.CheckSignatureSubgroup,
+			blstPublicKeys,
+			options.ValidatePublicKey,
+			messages,
+			dst,
+			randFn,
+			64,
+		)
+	if randErr != nil {
+		return false, randErr
+	}
+	return ok, nil
+}
+
+func (secretKey *BLSTMinSigSecretKey) Equal(other SecretKey) bool {
+	_other, err := toBLSTMinSigSecretKey(other)
+	if err != nil {
+		return false
+	}
+	return secretKey.s.Equals(_other.s)
+}
+
+func (secretKey *BLSTMinSigSecretKey) PublicKey() PublicKey {
+	return &BLSTMinSigPublicKey{new(blstMinSigPublicKey).From(secretKey.s)}
+}
+
+func (secretKey *BLSTMinSigSecretKey) Sign(message []byte) Signature {
+	return blstMinSigBackend{}.Sign(secretKey, message, minSigDST)
+}
+
+func (secretKey *BLSTMinSigSecretKey) ToBytes() []byte {
+	return secretKey.s.Serialize()
+}
+
+func (publicKey *BLSTMinSigPublicKey) FromBytes(compressed []byte) (PublicKey, error) {
+	if len(compressed) != MinSigPublicKeySize {
+		return nil, errPublicKeySize
+	}
+	p, err := blstValidate(blstDecode[blstMinSigPublicKey](compressed, MinSigPublicKeySize), compressed, true, publicKeyError)
+	if err != nil {
+		return nil, err
+	}
+	publicKey.p = p
+	return publicKey, nil
+}
+
+func (publicKey *BLSTMinSigPublicKey) ToBytes() []byte {
+	return publicKey.p.Compress()
+}
+
+func (publicKey *BLSTMinSigPublicKey) FromUncompressed(uncompressed []byte) (PublicKey, error) {
+	if err := checkUncompressedPublicKey(uncompressed, UncompressedSignatureSize); err != nil {
+		return nil, err
+	}
+	p, err := blstValidate(blstDecode[blstMinSigPublicKey](uncompressed, MinSigPublicKeySize), uncompressed, true, publicKeyError)
+	if err != nil {
+		return nil, err
+	}
+	publicKey.p = p
+	return publicKey, nil
+}
+
+func (publicKey *BLSTMinSigPublicKey) ToUncompressed() []byte {
+	return publicKey.p.Serialize()
+}
+
+func (publicKey *BLSTMinSigPublicKey) Equal(other PublicKey) bool {
+	_other, err := toBLSTMinSigPublicKey(other)
+	if err != nil {
+		return false
+	}
+	return publicKey.p.Equals(_other.p)
+}
+
+func (signature *BLSTMinSigSignature) FromBytes(compressed []byte) (Signature, error) {
+	if len(compressed) != MinSigSignatureSize {
+		return nil, errSignatureSize
+	}
+	p, err := blstValidate(blstDecode[blstMinSigSignature](compressed, MinSigSignatureSize), compressed, false, signatureError)
+	if err != nil {
+		return nil, err
+	}
+	signature.p = p
+	return signature, nil
+}
+
+func (signature *BLSTMinSigSignature) ToBytes() []byte {
+	return signature.p.Compress()
+}
+
+func (signature *BLSTMinSigSignature) FromUncompressed(uncompressed []byte) (Signature, error) {
+	if err := checkUncompressedSignature(uncompressed, UncompressedPublicKeySize); err != nil {
+		return nil, err
+	}
+	p, err := blstValidate(blstDecode[blstMinSigSignature](uncompressed, MinSigSignatureSize), uncompressed, false, signatureError)
+	if err != nil {
+		return nil, err
+	}
+	signature.p = p
+	return signature, nil
+}
+
+func (signature *BLSTMinSigSignature) ToUncompressed() []byte {
+	return signature.p.Serialize()
+}
+
+func (signature *BLSTMinSigSignature) Equal(other Signature) bool {
+	_other, err := toBLSTMinSigSignature(other)
+	if err != nil {
+		return false
+	}
+	return signature.p.Equals(_other.p)
+}
+
+func (signature *BLSTMinSigSignature) Verify(publicKey PublicKey, message []byte) bool {
+	return blstMinSigBackend{}.Verify(signature, publicKey, message, minSigDST, defaultValidationOptions)
+}
+
+func (signature *BLSTMinSigSignature) FastAggregateVerify(publicKeys []PublicKey, message []byte) bool {
+	return blstMinSigBackend{}.FastAggregateVerify(signature, publicKeys, message, minSigDST, defaultValidationOptions)
+}
+
+func (signature *BLSTMinSigSignature) AggregateVerify(publicKeys []PublicKey, messages [][]byte) bool {
+	return blstMinSigBackend{}.AggregateVerify(signature, publicKeys, messages, minSigDST, defaultValidationOptions)
+}
+
+func blstMinSigAggregateSignature(signatures []Signature) (Signature, error) {
+	blstSignatures, err := convertPoints(signatures, blstMinSigBackend{}, func(signature *BLSTMinSigSignature) *blstMinSigSignature { return signature.p })
+	if err != nil {
+		return nil, err
+	}
+	return &BLSTMinSigSignature{aggregatePoints(newBLSTG1Sum(), blstSignatures)}, nil
+}
+
+func blstMinSigAggregatePublicKey(publicKeys []PublicKey) (PublicKey, error) {
+	blstPublicKeys, err := toBLSTMinSigPublicKeys(publicKeys)
+	if err != nil {
+		return nil, err
+	}
+	return &BLSTMinSigPublicKey{aggregatePoints(newBLSTG2Sum(), blstPublicKeys)}, nil
+}
+
+func toBLSTMinSigSecretKey(secretKey SecretKey) (*BLSTMinSigSecretKey, error) {
+	return convertTo[*BLSTMinSigSecretKey](secretKey, blstMinSigBackend{})
+}
+
+func toBLSTMinSigPublicKey(publicKey PublicKey) (*BLSTMinSigPublicKey, error) {
+	return convertTo[*BLSTMinSigPublicKey](publicKey, blstMinSigBackend{})
+}
+
+func toBLSTMinSigPublicKeys(publicKeys []PublicKey) ([]*blstMinSigPublicKey, error) {
+	return convertPoints(publicKeys, blstMinSigBackend{}, func(publicKey *BLSTMinSigPublicKey) *blstMinSigPublicKey { return publicKey.p })
+}
+
+func toBLSTMinSigSignature(signature Signature) (*BLSTMinSigSignature, error) {
+	return convertTo[*BLSTMinSigSignature](signature, blstMinSigBackend{})
+}
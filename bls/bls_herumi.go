@@ -1,7 +1,12 @@
 package cross_bls
 
 import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+
 	herumi "github.com/herumi/bls-eth-go-binary/bls"
+	kilic "github.com/kilic/bls12-381"
 )
 
 type herumiPublicKey = herumi.PublicKey
@@ -20,6 +25,347 @@ type HerumiSignature struct {
 	p *herumiSignature
 }
 
+// herumiDST is the hash to curve tag fixed in herumi ethereum mode.
+// Messages with other tags are hashed to curve with kilic.
+var herumiDST = []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_")
+
+type herumiBackend struct{}
+
+func (herumiBackend) Name() string {
+	return libHerumi
+}
+
+func (herumiBackend) Variant() Variant {
+	return MinPublicKeySize
+}
+
+func (herumiBackend) initialize() {
+	initHerumi()
+}
+
+func (herumiBackend) RandSecretKey() SecretKey {
+	return randHerumiSecretKey()
+}
+
+func (herumiBackend) SecretKeyFromBytes(in []byte) (SecretKey, error) {
+	return herumiSecretKeyFromBytes(in)
+}
+
+func (herumiBackend) PublicKeyFromBytes(compressed []byte) (PublicKey, error) {
+	return new(HerumiPublicKey).FromBytes(compressed)
+}
+
+func (herumiBackend) SignatureFromBytes(compressed []byte) (Signature, error) {
+	return new(HerumiSignature).FromBytes(compressed)
+}
+
+func (herumiBackend) PublicKeyFromUncompressed(uncompressed []byte) (PublicKey, error) {
+	return new(HerumiPublicKey).FromUncompressed(uncompressed)
+}
+
+func (herumiBackend) SignatureFromUncompressed(uncompressed []byte) (Signature, error) {
+	return new(HerumiSignature).FromUncompressed(uncompressed)
+}
+
+func (herumiBackend) publicKeyFromBytesUnchecked(encoded []byte) (PublicKey, error) {
+	if len(encoded) != PublicKeySize && len(encoded) != UncompressedPublicKeySize {
+		return nil, errPublicKeySize
+	}
+	p, ok := herumiG1FromBytesUnchecked(encoded)
+	if !ok {
+		return nil, publicKeyError(encoded, false, nil)
+	}
+	return &HerumiPublicKey{herumi.CastToPublicKey(p)}, nil
+}
+
+func (herumiBackend) signatureFromBytesUnchecked(encoded []byte) (Signature, error) {
+	if len(encoded) != SignatureSize && len(encoded) != UncompressedSignatureSize {
+		return nil, errSignatureSize
+	}
+	p, ok := herumiG2FromBytesUnchecked(encoded)
+	if !ok {
+		return nil, signatureError(encoded, true, nil)
+	}
+	return &HerumiSignature{herumi.CastToSign(p)}, nil
+}
+
+func (herumiBackend) AggregatePublicKeys(publicKeys []PublicKey) (PublicKey, error) {
+	return herumiAggregatePublicKey(publicKeys)
+}
+
+func (herumiBackend) AggregateSignatures(signatures []Signature) (Signature, error) {
+	return herumiAggregateSignature(signatures)
+}
+
+func (herumiBackend) mulPublicKey(publicKey PublicKey, scalar *big.Int) (PublicKey, error) {
+	_publicKey, err := toHerumiPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	fr, err := herumiScalar(scalar)
+	if err != nil {
+		return nil, err
+	}
+	p := new(herumi.G1)
+	herumi.G1Mul(p, herumi.CastFromPublicKey(_publicKey.p), fr)
+	return &HerumiPublicKey{herumi.CastToPublicKey(p)}, nil
+}
+
+func (herumiBackend) mulSignature(signature Signature, scalar *big.Int) (Signature, error) {
+	_signature, err := toHerumiSignature(signature)
+	if err != nil {
+		return nil, err
+	}
+	fr, err := herumiScalar(scalar)
+	if err != nil {
+		return nil, err
+	}
+	p := new(herumi.G2)
+	herumi.G2Mul(p, herumi.CastFromSign(_signature.p), fr)
+	return &HerumiSignature{herumi.CastToSign(p)}, nil
+}
+
+func (herumiBackend) NewPublicKeyAggregator() PublicKeyAggregator {
+	point := func(publicKey *HerumiPublicKey) *herumi.G1 { return herumi.CastFromPublicKey(publicKey.p) }
+	to := func(sum *herumi.G1) *HerumiPublicKey { return &HerumiPublicKey{herumi.CastToPublicKey(sum)} }
+	return newPublicKeyAggregator(herumiBackend{}, newHerumiG1Sum(), point, to)
+}
+
+func (herumiBackend) NewSignatureAggregator() SignatureAggregator {
+	point := func(signature *HerumiSignature) *herumi.G2 { return herumi.CastFromSign(signature.p) }
+	to := func(sum *herumi.G2) *HerumiSignature { return &HerumiSignature{herumi.CastToSign(sum)} }
+	return newSignatureAggregator(herumiBackend{}, newHerumiG2Sum(), point, to)
+}
+
+func (herumiBackend) Sign(secretKey SecretKey, message, dst []byte) Signature {
+	_secretKey, err := toHerumiSecretKey(secretKey)
+	if err != nil {
+		return nil
+	}
+	s := _secretKey.s
+	if bytes.Equal(dst, herumiDST) && len(message) != 0 {
+		return &HerumiSignature{s.Sign(string(message))}
+	}
+	M, err := herumiHashToCurve(message, dst)
+	if err != nil {
+		return nil
+	}
+	signature := new(herumi.G2)
+	herumi.G2Mul(signature, M, herumi.CastFromSecretKey(s))
+	return &HerumiSignature{herumi.CastToSign(signature)}
+}
+
+// hashToSignatureGroup hashes like Sign, with herumi for its own tag and with kilic for others.
+func (herumiBackend) hashToSignatureGroup(message, dst []byte) (Signature, error) {
+	if bytes.Equal(dst, herumiDST) && len(message) != 0 {
+		M := new(herumi.G2)
+		if err := M.HashAndMapTo(message); err != nil {
+			return nil, err
+		}
+		return &HerumiSignature{herumi.CastToSign(M)}, nil
+	}
+	M, err := herumiHashToCurve(message, dst)
+	if err != nil {
+		return nil, err
+	}
+	return &HerumiSignature{herumi.CastToSign(M)}, nil
+}
+
+func (herumiBackend) Verify(signature Signature, publicKey PublicKey, message, dst []byte, options ValidationOptions) bool {
+	_signature, err := toHerumiSignature(signature)
+	if err != nil {
+		return false
+	}
+	herumiPublicKeys, err := toHerumiPublicKeys([]PublicKey{publicKey})
+	if err != nil {
+		return false
+	}
+	if !herumiValidate(_signature.p, herumiPublicKeys, options) {
+		return false
+	}
+	if bytes.Equal(dst, herumiDST) && len(message) != 0 {
+		return _signature.p.
+			Verify(
+				&herumiPublicKeys[0],
+				string(message),
+			)
+	}
+	return herumiVerifyPairing(_signature.p, herumiPublicKeys, [][]byte{message}, dst)
+}
+
+func (herumiBackend) FastAggregateVerify(signature Signature, publicKeys []PublicKey, message, dst []byte, options ValidationOptions) bool {
+	if len(publicKeys) == 0 {
+		return false
+	}
+	_signature, err := toHerumiSignature(signature)
+	if err != nil {
+		return false
+	}
+	herumiPublicKeys, err := toHerumiPublicKeys(publicKeys)
+	if err != nil {
+		return false
+	}
+	if !herumiValidate(_signature.p, herumiPublicKeys, options) {
+		return false
+	}
+	if bytes.Equal(dst, herumiDST) {
+		return _signature.p.
+			FastAggregateVerify(
+				herumiPublicKeys,
+				message[:],
+			)
+	}
+	aggregated := new(herumiPublicKey)
+	for i := 0; i < len(herumiPublicKeys); i++ {
+		aggregated.Add(&herumiPublicKeys[i])
+	}
+	return herumiVerifyPairing(_signature.p, []herumiPublicKey{*aggregated}, [][]byte{message}, dst)
+}
+
+func (herumiBackend) AggregateVerify(signature Signature, publicKeys []PublicKey, messages [][]byte, dst []byte, options ValidationOptions) bool {
+	size := len(publicKeys)
+	if size == 0 {
+		return false
+	}
+	if len(messages) != size {
+		return false
+	}
+	_signature, err := toHerumiSignature(signature)
+	if err != nil {
+		return false
+	}
+	herumiPublicKeys, err := toHerumiPublicKeys(publicKeys)
+	if err != nil {
+		return false
+	}
+	if !herumiValidate(_signature.p, herumiPublicKeys, options) {
+		return false
+	}
+	// herumi aggregate verification expects 32 bytes messages
+	native := bytes.Equal(dst, herumiDST)
+	for i := 0; i < size && native; i++ {
+		native = len(messages[i]) == 32
+	}
+	if !native {
+		return herumiVerifyPairing(_signature.p, herumiPublicKeys, messages, dst)
+	}
+	_messages := []byte{}
+	for i := 0; i < size; i++ {
+		_messages = append(_messages, messages[i][:]...)
+	}
+	// herumi AggregateVerify rejects duplicate messages, which is only a rule of Basic ciphersuite
+	// and is checked by suites, so that PoP and Aug behave the same as on other backends
+	return _signature.p.
+		AggregateVerifyNoCheck(
+			herumiPublicKeys,
+			_messages,
+		)
+}
+
+func (herumiBackend) VerifyMultipleSignatures(sets []SignatureSet, dst []byte, options ValidationOptions) (bool, error) {
+	size := len(sets)
+	herumiSignatures := make([]herumiSignature, size)
+	herumiPublicKeys := make([]herumiPublicKey, size)
+	for i := 0; i < size; i++ {
+		signature, err := toHerumiSignature(sets[i].Signature)
+		if err != nil {
+			return false, err
+		}
+		publicKey, err := toHerumiPublicKey(sets[i].PublicKey)
+		if err != nil {
+			return false, err
+		}
+		if !herumiValidate(signature.p, []herumiPublicKey{*publicKey.p}, options) {
+			return false, nil
+		}
+		herumiSignatures[i] = *signature.p
+		herumiPublicKeys[i] = *publicKey.p
+	}
+	// herumi multi verification expects 32 bytes messages
+	native := bytes.Equal(dst, herumiDST)
+	for i := 0; i < size && native; i++ {
+		native = len(sets[i].Message) == 32
+	}
+	if native {
+		_messages := []byte{}
+		for i := 0; i < size; i++ {
+			_messages = append(_messages, sets[i].Message...)
+		}
+		return herumi.MultiVerify(herumiSignatures, herumiPublicKeys, _messages), nil
+	}
+	// e(g1, sum(r_i * sig_i)) == e(r_0 * pk_0, H(m_0)) * ... * e(r_(n-1) * pk_(n-1), H(m_(n-1)))
+	g1s := make([]herumi.G1, size+1)
+	g2s := make([]herumi.G2, size+1)
+	sigs := make([]herumi.G2, size)
+	scalars := make([]herumi.Fr, size)
+	for i := 0; i < size; i++ {
+		r, err := randBatchScalar()
+		if err != nil {
+			return false, err
+		}
+		// herumi expects little endian scalars
+		for j, k := 0, len(r)-1; j < k; j, k = j+1, k-1 {
+			r[j], r[k] = r[k], r[j]
+		}
+		if err := scalars[i].SetLittleEndian(r); err != nil {
+			return false, err
+		}
+		M, err := herumiHashToCurve(sets[i].Message, dst)
+		if err != nil {
+			return false, err
+		}
+		herumi.G1Mul(&g1s[i], herumi.CastFromPublicKey(&herumiPublicKeys[i]), &scalars[i])
+		g2s[i] = *M
+		sigs[i] = *herumi.CastFromSign(&herumiSignatures[i])
+	}
+	generator := new(herumiPublicKey)
+	herumi.BlsGetGeneratorOfPublicKey(generator)
+	herumi.G1Neg(&g1s[size], herumi.CastFromPublicKey(generator))
+	herumi.G2MulVec(&g2s[size], sigs, scalars)
+	e := new(herumi.GT)
+	herumi.MillerLoopVec(e, g1s, g2s)
+	herumi.FinalExp(e, e)
+	return e.IsOne(), nil
+}
+
+// herumiHashToCurve hashes message to G2 with given tag using kilic,
+// and brings the point into herumi.
+func herumiHashToCurve(message, dst []byte) (*herumi.G2, error) {
+	g := kilic.NewG2()
+	M, err := g.HashToCurve(message, dst)
+	if err != nil {
+		return nil, err
+	}
+	p := new(herumi.G2)
+	if err := p.Deserialize(g.ToCompressed(M)); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// herumiVerifyPairing checks e(g1, signature) == e(pk_0, H(m_0)) * ... * e(pk_(n-1), H(m_(n-1))).
+func herumiVerifyPairing(signature *herumiSignature, publicKeys []herumiPublicKey, messages [][]byte, dst []byte) bool {
+	size := len(publicKeys)
+	g1s := make([]herumi.G1, size+1)
+	g2s := make([]herumi.G2, size+1)
+	for i := 0; i < size; i++ {
+		M, err := herumiHashToCurve(messages[i], dst)
+		if err != nil {
+			return false
+		}
+		g1s[i] = *herumi.CastFromPublicKey(&publicKeys[i])
+		g2s[i] = *M
+	}
+	generator := new(herumiPublicKey)
+	herumi.BlsGetGeneratorOfPublicKey(generator)
+	herumi.G1Neg(&g1s[size], herumi.CastFromPublicKey(generator))
+	g2s[size] = *herumi.CastFromSign(signature)
+	e := new(herumi.GT)
+	herumi.MillerLoopVec(e, g1s, g2s)
+	herumi.FinalExp(e, e)
+	return e.IsOne()
+}
+
 func randHerumiSecretKey() SecretKey {
 	secretKey := new(herumi.SecretKey)
 	secretKey.SetByCSPRNG()
@@ -33,13 +379,17 @@ func herumiSecretKeyFromBytes(in []byte) (SecretKey, error) {
 	secretKey := new(herumi.SecretKey)
 	err := secretKey.Deserialize(in)
 	if err != nil {
-		return nil, errInvalidSecretKey
+		return nil, errSecretKeyNonCanonical
 	}
 	return &HerumiSecretKey{secretKey}, nil
 }
 
 func (secretKey *HerumiSecretKey) Equal(other SecretKey) bool {
-	return secretKey.s.IsEqual(other.(*HerumiSecretKey).s)
+	_other, err := toHerumiSecretKey(other)
+	if err != nil {
+		return false
+	}
+	return secretKey.s.IsEqual(_other.s)
 }
 
 func (secretKey *HerumiSecretKey) PublicKey() PublicKey {
@@ -47,29 +397,22 @@ func (secretKey *HerumiSecretKey) PublicKey() PublicKey {
 }
 
 func (secretKey *HerumiSecretKey) Sign(message []byte) Signature {
-	herumiSignature := secretKey.s.Sign(string(message))
-	return &HerumiSignature{herumiSignature}
+	return herumiBackend{}.Sign(secretKey, message, dst)
 }
 
 func (secretKey *HerumiSecretKey) ToBytes() []byte {
 	return secretKey.s.Serialize()
 }
 
-func (publicKey *HerumiPublicKey) FromBytes(compresed []byte) (PublicKey, error) {
-	if len(compresed) != PublicKeySize {
+func (publicKey *HerumiPublicKey) FromBytes(compressed []byte) (PublicKey, error) {
+	if len(compressed) != PublicKeySize {
 		return nil, errPublicKeySize
 	}
-	herumiPublicKey := new(herumiPublicKey)
-	if err := herumiPublicKey.Deserialize(compresed); err != nil {
+	p, err := herumiDecode[herumiPublicKey](compressed, false, publicKeyError)
+	if err != nil {
 		return nil, err
 	}
-	if !herumiPublicKey.IsValidOrder() {
-		return nil, errInvalidPublicKey
-	}
-	if herumiPublicKey.IsZero() {
-		return nil, errZeroPublicKey
-	}
-	publicKey.p = herumiPublicKey
+	publicKey.p = p
 	return publicKey, nil
 }
 
@@ -77,25 +420,39 @@ func (publicKey *HerumiPublicKey) ToBytes() []byte {
 	return publicKey.p.Serialize()
 }
 
+func (publicKey *HerumiPublicKey) FromUncompressed(uncompressed []byte) (PublicKey, error) {
+	if err := checkUncompressedPublicKey(uncompressed, UncompressedPublicKeySize); err != nil {
+		return nil, err
+	}
+	p, err := herumiDecode[herumiPublicKey](uncompressed, false, publicKeyError)
+	if err != nil {
+		return nil, err
+	}
+	publicKey.p = p
+	return publicKey, nil
+}
+
+func (publicKey *HerumiPublicKey) ToUncompressed() []byte {
+	return publicKey.p.SerializeUncompressed()
+}
+
 func (publicKey *HerumiPublicKey) Equal(other PublicKey) bool {
-	return publicKey.p.IsEqual(other.(*HerumiPublicKey).p)
+	_other, err := toHerumiPublicKey(other)
+	if err != nil {
+		return false
+	}
+	return publicKey.p.IsEqual(_other.p)
 }
 
-func (signature *HerumiSignature) FromBytes(compresed []byte) (Signature, error) {
-	if len(compresed) != SignatureSize {
+func (signature *HerumiSignature) FromBytes(compressed []byte) (Signature, error) {
+	if len(compressed) != SignatureSize {
 		return nil, errSignatureSize
 	}
-	herumiSignature := new(herumiSignature)
-	if err := herumiSignature.Deserialize(compresed); err != nil {
+	p, err := herumiDecode[herumiSignature](compressed, true, signatureError)
+	if err != nil {
 		return nil, err
 	}
-	if !herumiSignature.IsValidOrder() {
-		return nil, errInvalidSignature
-	}
-	if herumiSignature.IsZero() {
-		return nil, errZeroSignature
-	}
-	signature.p = herumiSignature
+	signature.p = p
 	return signature, nil
 }
 
@@ -103,70 +460,288 @@ func (signature *HerumiSignature) ToBytes() []byte {
 	return signature.p.Serialize()
 }
 
+func (signature *HerumiSignature) FromUncompressed(uncompressed []byte) (Signature, error) {
+	if err := checkUncompressedSignature(uncompressed, UncompressedSignatureSize); err != nil {
+		return nil, err
+	}
+	p, err := herumiDecode[herumiSignature](uncompressed, true, signatureError)
+	if err != nil {
+		return nil, err
+	}
+	signature.p = p
+	return signature, nil
+}
+
+func (signature *HerumiSignature) ToUncompressed() []byte {
+	return signature.p.SerializeUncompressed()
+}
+
 func (signature *HerumiSignature) Equal(other Signature) bool {
-	return signature.p.IsEqual(other.(*HerumiSignature).p)
+	_other, err := toHerumiSignature(other)
+	if err != nil {
+		return false
+	}
+	return signature.p.IsEqual(_other.p)
 }
 
 func (signature *HerumiSignature) Verify(publicKey PublicKey, message []byte) bool {
-	return signature.p.
-		Verify(
-			publicKey.(*HerumiPublicKey).p,
-			string(message),
-		)
+	return herumiBackend{}.Verify(signature, publicKey, message, dst, defaultValidationOptions)
 }
 
 func (signature *HerumiSignature) FastAggregateVerify(publicKeys []PublicKey, message []byte) bool {
-	if len(publicKeys) == 0 {
-		return false
+	return herumiBackend{}.FastAggregateVerify(signature, publicKeys, message, dst, defaultValidationOptions)
+}
+
+func (signature *HerumiSignature) AggregateVerify(publicKeys []PublicKey, messages [][]byte) bool {
+	return herumiBackend{}.AggregateVerify(signature, publicKeys, messages, dst, defaultValidationOptions)
+}
+
+func herumiAggregateSignature(signatures []Signature) (Signature, error) {
+	points, err := convertPoints(signatures, herumiBackend{}, func(signature *HerumiSignature) *herumi.G2 { return herumi.CastFromSign(signature.p) })
+	if err != nil {
+		return nil, err
 	}
-	herumiPublicKeys := make([]herumiPublicKey, len(publicKeys))
-	for i := 0; i < len(publicKeys); i++ {
-		herumiPublicKeys[i] = *publicKeys[i].(*HerumiPublicKey).p
+	sum := aggregatePoints(newHerumiG2Sum(), points)
+	return &HerumiSignature{herumi.CastToSign(sum)}, nil
+}
+
+func herumiAggregatePublicKey(publicKeys []PublicKey) (PublicKey, error) {
+	points, err := convertPoints(publicKeys, herumiBackend{}, func(publicKey *HerumiPublicKey) *herumi.G1 { return herumi.CastFromPublicKey(publicKey.p) })
+	if err != nil {
+		return nil, err
 	}
-	return signature.p.
-		FastAggregateVerify(
-			herumiPublicKeys,
-			message[:],
-		)
+	sum := aggregatePoints(newHerumiG1Sum(), points)
+	return &HerumiPublicKey{herumi.CastToPublicKey(sum)}, nil
 }
 
-func (signature *HerumiSignature) AggregateVerify(publicKeys []PublicKey, messages [][]byte) bool {
-	size := len(publicKeys)
-	if size == 0 {
-		return false
+// herumiPoint is the method set that herumi points, public keys and signatures share.
+type herumiPoint[T any] interface {
+	*T
+	Deserialize(buf []byte) error
+	DeserializeUncompressed(buf []byte) error
+	IsZero() bool
+	IsValidOrder() bool
+}
+
+// herumiDecode decodes a compressed or an uncompressed point on G1 or G2 that is in the subgroup
+// and is not the point at infinity. fail returns the error of a rejected point.
+func herumiDecode[T any, P herumiPoint[T]](encoded []byte, onG2 bool, fail pointError) (*T, error) {
+	compressedSize := fpByteSize
+	if onG2 {
+		compressedSize = 2 * fpByteSize
 	}
-	if len(messages) != size {
+	p := P(new(T))
+	var err error
+	if len(encoded) == compressedSize {
+		err = p.Deserialize(encoded)
+	} else {
+		err = p.DeserializeUncompressed(encoded)
+	}
+	if err != nil || p.IsZero() || !p.IsValidOrder() {
+		return nil, fail(encoded, onG2, herumiReason(encoded, onG2))
+	}
+	return p, nil
+}
+
+// herumiValidate applies verification time checks selected in options.
+func herumiValidate[S, P any, SP herumiPoint[S], PP herumiPoint[P]](signature *S, publicKeys []P, options ValidationOptions) bool {
+	if options.CheckSignatureSubgroup && !SP(signature).IsValidOrder() {
 		return false
 	}
-	herumiPublicKeys := make([]herumiPublicKey, size)
-	_messages := []byte{}
-	for i := 0; i < size; i++ {
-		herumiPublicKeys[i] = *publicKeys[i].(*HerumiPublicKey).p
-		_messages = append(_messages, messages[i][:]...)
+	if options.ValidatePublicKey {
+		for i := 0; i < len(publicKeys); i++ {
+			if PP(&publicKeys[i]).IsZero() || !PP(&publicKeys[i]).IsValidOrder() {
+				return false
+			}
+		}
 	}
-	return signature.p.
-		AggregateVerify(
-			herumiPublicKeys,
-			_messages,
-		)
+	return true
 }
 
-func herumiAggregateSignature(signatures []Signature) Signature {
-	size := len(signatures)
-	herimuSignatures := make([]herumiSignature, size)
-	for i := 0; i < size; i++ {
-		herimuSignatures[i] = *signatures[i].(*HerumiSignature).p
+// herumiG1Sum sums G1 points of public keys or of minimal size signatures.
+type herumiG1Sum struct {
+	sum herumi.G1
+}
+
+func newHerumiG1Sum() pointSum[*herumi.G1] {
+	return new(herumiG1Sum)
+}
+
+func (sum *herumiG1Sum) add(p *herumi.G1) {
+	herumi.G1Add(&sum.sum, &sum.sum, p)
+}
+
+func (sum *herumiG1Sum) sub(p *herumi.G1) {
+	herumi.G1Sub(&sum.sum, &sum.sum, p)
+}
+
+func (sum *herumiG1Sum) result() *herumi.G1 {
+	result := sum.sum
+	return &result
+}
+
+// herumiG2Sum sums G2 points of signatures or of minimal signature size public keys.
+type herumiG2Sum struct {
+	sum herumi.G2
+}
+
+func newHerumiG2Sum() pointSum[*herumi.G2] {
+	return new(herumiG2Sum)
+}
+
+func (sum *herumiG2Sum) add(p *herumi.G2) {
+	herumi.G2Add(&sum.sum, &sum.sum, p)
+}
+
+func (sum *herumiG2Sum) sub(p *herumi.G2) {
+	herumi.G2Sub(&sum.sum, &sum.sum, p)
+}
+
+func (sum *herumiG2Sum) result() *herumi.G2 {
+	result := sum.sum
+	return &result
+}
+
+func toHerumiSecretKey(secretKey SecretKey) (*HerumiSecretKey, error) {
+	return convertTo[*HerumiSecretKey](secretKey, herumiBackend{})
+}
+
+func toHerumiPublicKey(publicKey PublicKey) (*HerumiPublicKey, error) {
+	return convertTo[*HerumiPublicKey](publicKey, herumiBackend{})
+}
+
+func toHerumiPublicKeys(publicKeys []PublicKey) ([]herumiPublicKey, error) {
+	return convertPoints(publicKeys, herumiBackend{}, func(publicKey *HerumiPublicKey) herumiPublicKey { return *publicKey.p })
+}
+
+func toHerumiSignature(signature Signature) (*HerumiSignature, error) {
+	return convertTo[*HerumiSignature](signature, herumiBackend{})
+}
+
+// herumi checks the order of points in deserialization unless it is disabled process wide with
+// VerifyPublicKeyOrder and VerifySignatureOrder, so points are decoded without the subgroup check
+// from their coordinates here. Infinity is decoded by suites.
+
+// herumiFp returns the field element of big endian encoding.
+func herumiFp(in []byte) (herumi.Fp, bool) {
+	var fe herumi.Fp
+	return fe, fe.SetString(hex.EncodeToString(in), 16) == nil
+}
+
+// herumiScalar returns scalar modulo group order as a herumi scalar.
+func herumiScalar(scalar *big.Int) (*herumi.Fr, error) {
+	fr := new(herumi.Fr)
+	if err := fr.SetString(new(big.Int).Mod(scalar, groupOrder).String(), 10); err != nil {
+		return nil, err
 	}
-	aggregatedSignature := new(herumiSignature)
-	aggregatedSignature.Aggregate(herimuSignatures)
-	return &HerumiSignature{aggregatedSignature}
+	return fr, nil
 }
 
-func herumiAggregatePublicKey(publicKeys []PublicKey) PublicKey {
-	size := len(publicKeys)
-	aggregatedPublicKey := new(herumiPublicKey)
-	for i := 0; i < size; i++ {
-		aggregatedPublicKey.Add(publicKeys[i].(*HerumiPublicKey).p)
+// herumiReason returns ErrNotInSubgroup if the encoded point that herumi rejected is on G1 or G2
+// curve but out of subgroup, as herumi does not report why deserialization fails.
+func herumiReason(encoded []byte, onG2 bool) error {
+	if onG2 {
+		if p, ok := herumiG2FromBytesUnchecked(encoded); ok && !p.IsValidOrder() {
+			return ErrNotInSubgroup
+		}
+		return nil
+	}
+	if p, ok := herumiG1FromBytesUnchecked(encoded); ok && !p.IsValidOrder() {
+		return ErrNotInSubgroup
+	}
+	return nil
+}
+
+// herumiG1FromBytesUnchecked decodes a compressed or uncompressed point on G1 curve.
+func herumiG1FromBytesUnchecked(encoded []byte) (*herumi.G1, bool) {
+	compressed := len(encoded) == fpByteSize
+	if encoded[0]&0x40 != 0 || (encoded[0]&0x80 != 0) != compressed {
+		return nil, false
+	}
+	p := new(herumi.G1)
+	x, ok := herumiFp(append([]byte{encoded[0] & 0x1f}, encoded[1:fpByteSize]...))
+	if !ok {
+		return nil, false
+	}
+	// y^2 = x^3 + 4
+	var rhs, b herumi.Fp
+	herumi.FpSqr(&rhs, &x)
+	herumi.FpMul(&rhs, &rhs, &x)
+	b.SetInt64(4)
+	herumi.FpAdd(&rhs, &rhs, &b)
+	if compressed {
+		if !herumi.FpSquareRoot(&p.Y, &rhs) {
+			return nil, false
+		}
+		if p.Y.IsNegative() != (encoded[0]&0x20 != 0) {
+			herumi.FpNeg(&p.Y, &p.Y)
+		}
+	} else {
+		if encoded[0]&0x20 != 0 {
+			return nil, false
+		}
+		if p.Y, ok = herumiFp(encoded[fpByteSize:]); !ok {
+			return nil, false
+		}
+		var y2 herumi.Fp
+		herumi.FpSqr(&y2, &p.Y)
+		if !y2.IsEqual(&rhs) {
+			return nil, false
+		}
+	}
+	p.X = x
+	p.Z.SetInt64(1)
+	return p, true
+}
+
+// herumiG2FromBytesUnchecked decodes a compressed or uncompressed point on G2 curve.
+func herumiG2FromBytesUnchecked(encoded []byte) (*herumi.G2, bool) {
+	compressed := len(encoded) == 2*fpByteSize
+	if encoded[0]&0x40 != 0 || (encoded[0]&0x80 != 0) != compressed {
+		return nil, false
+	}
+	p := new(herumi.G2)
+	var ok bool
+	if p.X.D[1], ok = herumiFp(append([]byte{encoded[0] & 0x1f}, encoded[1:fpByteSize]...)); !ok {
+		return nil, false
+	}
+	if p.X.D[0], ok = herumiFp(encoded[fpByteSize : 2*fpByteSize]); !ok {
+		return nil, false
+	}
+	// y^2 = x^3 + 4(u + 1)
+	var rhs, b herumi.Fp2
+	herumi.Fp2Sqr(&rhs, &p.X)
+	herumi.Fp2Mul(&rhs, &rhs, &p.X)
+	b.D[0].SetInt64(4)
+	b.D[1].SetInt64(4)
+	herumi.Fp2Add(&rhs, &rhs, &b)
+	if compressed {
+		if !herumi.Fp2SquareRoot(&p.Y, &rhs) {
+			return nil, false
+		}
+		negative := p.Y.D[1].IsNegative()
+		if p.Y.D[1].IsZero() {
+			negative = p.Y.D[0].IsNegative()
+		}
+		if negative != (encoded[0]&0x20 != 0) {
+			herumi.Fp2Neg(&p.Y, &p.Y)
+		}
+	} else {
+		if encoded[0]&0x20 != 0 {
+			return nil, false
+		}
+		if p.Y.D[1], ok = herumiFp(encoded[2*fpByteSize : 3*fpByteSize]); !ok {
+			return nil, false
+		}
+		if p.Y.D[0], ok = herumiFp(encoded[3*fpByteSize:]); !ok {
+			return nil, false
+		}
+		var y2 herumi.Fp2
+		herumi.Fp2Sqr(&y2, &p.Y)
+		if !y2.IsEqual(&rhs) {
+			return nil, false
+		}
 	}
-	return &HerumiPublicKey{aggregatedPublicKey}
+	p.Z.D[0].SetInt64(1)
+	return p, true
 }
@@ -0,0 +1,469 @@
+package cross_bls
+
+import (
+	"math/big"
+	"sync"
+
+	herumi "github.com/herumi/bls-eth-go-binary/bls"
+	kilic "github.com/kilic/bls12-381"
+)
+
+type herumiMinSigPublicKey = herumi.G2
+type herumiMinSigSignature = herumi.G1
+
+type HerumiMinSigPublicKey struct {
+	p *herumiMinSigPublicKey
+}
+
+type HerumiMinSigSecretKey struct {
+	s *herumiSecretKey
+}
+
+type HerumiMinSigSignature struct {
+	p *herumiMinSigSignature
+}
+
+// herumi ethereum mode fixes public keys in G1, so minimal signature size variant
+// is built on herumi curve operations. Messages are hashed to curve with kilic.
+var herumiMinSigOnce sync.Once
+var herumiG2Generator *herumi.G2
+
+type herumiMinSigBackend struct{}
+
+func (herumiMinSigBackend) Name() string {
+	return libHerumiMinSig
+}
+
+func (herumiMinSigBackend) Variant() Variant {
+	return MinSignatureSize
+}
+
+func (herumiMinSigBackend) initialize() {
+	initHerumiMinSig()
+}
+
+func (herumiMinSigBackend) RandSecretKey() SecretKey {
+	return &HerumiMinSigSecretKey{randHerumiSecretKey().(*HerumiSecretKey).s}
+}
+
+func (herumiMinSigBackend) SecretKeyFromBytes(in []byte) (SecretKey, error) {
+	secretKey, err := herumiSecretKeyFromBytes(in)
+	if err != nil {
+		return nil, err
+	}
+	return &HerumiMinSigSecretKey{secretKey.(*HerumiSecretKey).s}, nil
+}
+
+func (herumiMinSigBackend) PublicKeyFromBytes(compressed []byte) (PublicKey, error) {
+	return new(HerumiMinSigPublicKey).FromBytes(compressed)
+}
+
+func (herumiMinSigBackend) SignatureFromBytes(compressed []byte) (Signature, error) {
+	return new(HerumiMinSigSignature).FromBytes(compressed)
+}
+
+func (herumiMinSigBackend) PublicKeyFromUncompressed(uncompressed []byte) (PublicKey, error) {
+	return new(HerumiMinSigPublicKey).FromUncompressed(uncompressed)
+}
+
+func (herumiMinSigBackend) SignatureFromUncompressed(uncompressed []byte) (Signature, error) {
+	return new(HerumiMinSigSignature).FromUncompressed(uncompressed)
+}
+
+func (herumiMinSigBackend) publicKeyFromBytesUnchecked(encoded []byte) (PublicKey, error) {
+	if len(encoded) != MinSigPublicKeySize && len(encoded) != UncompressedSignatureSize {
+		return nil, errPublicKeySize
+	}
+	p, ok := herumiG2FromBytesUnchecked(encoded)
+	if !ok {
+		return nil, publicKeyError(encoded, true, nil)
+	}
+	return &HerumiMinSigPublicKey{p}, nil
+}
+
+func (herumiMinSigBackend) signatureFromBytesUnchecked(encoded []byte) (Signature, error) {
+	if len(encoded) != MinSigSignatureSize && len(encoded) != UncompressedPublicKeySize {
+		return nil, errSignatureSize
+	}
+	p, ok := herumiG1FromBytesUnchecked(encoded)
+	if !ok {
+		return nil, signatureError(encoded, false, nil)
+	}
+	return &HerumiMinSigSignature{p}, nil
+}
+
+func (herumiMinSigBackend) AggregatePublicKeys(publicKeys []PublicKey) (PublicKey, error) {
+	return herumiMinSigAggregatePublicKey(publicKeys)
+}
+
+func (herumiMinSigBackend) AggregateSignatures(signatures []Signature) (Signature, error) {
+	return herumiMinSigAggregateSignature(signatures)
+}
+
+func (herumiMinSigBackend) mulPublicKey(publicKey PublicKey, scalar *big.Int) (PublicKey, error) {
+	_publicKey, err := toHerumiMinSigPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	fr, err := herumiScalar(scalar)
+	if err != nil {
+		return nil, err
+	}
+	p := new(herumiMinSigPublicKey)
+	herumi.G2Mul(p, _publicKey.p, fr)
+	return &HerumiMinSigPublicKey{p}, nil
+}
+
+func (herumiMinSigBackend) mulSignature(signature Signature, scalar *big.Int) (Signature, error) {
+	_signature, err := toHerumiMinSigSignature(signature)
+	if err != nil {
+		return nil, err
+	}
+	fr, err := herumiScalar(scalar)
+	if err != nil {
+		return nil, err
+	}
+	p := new(herumiMinSigSignature)
+	herumi.G1Mul(p, _signature.p, fr)
+	return &HerumiMinSigSignature{p}, nil
+}
+
+func (herumiMinSigBackend) NewPublicKeyAggregator() PublicKeyAggregator {
+	point := func(publicKey *HerumiMinSigPublicKey) *herumi.G2 { return publicKey.p }
+	to := func(sum *herumi.G2) *HerumiMinSigPublicKey { return &HerumiMinSigPublicKey{sum} }
+	return newPublicKeyAggregator(herumiMinSigBackend{}, newHerumiG2Sum(), point, to)
+}
+
+func (herumiMinSigBackend) NewSignatureAggregator() SignatureAggregator {
+	point := func(signature *HerumiMinSigSignature) *herumi.G1 { return signature.p }
+	to := func(sum *herumi.G1) *HerumiMinSigSignature { return &HerumiMinSigSignature{sum} }
+	return newSignatureAggregator(herumiMinSigBackend{}, newHerumiG1Sum(), point, to)
+}
+
+func (herumiMinSigBackend) Sign(secretKey SecretKey, message, dst []byte) Signature {
+	_secretKey, err := toHerumiMinSigSecretKey(secretKey)
+	if err != nil {
+		return nil
+	}
+	M, err := herumiHashToG1(message, dst)
+	if err != nil {
+		return nil
+	}
+	signature := new(herumiMinSigSignature)
+	herumi.G1Mul(signature, M, herumi.CastFromSecretKey(_secretKey.s))
+	return &HerumiMinSigSignature{signature}
+}
+
+// hashToSignatureGroup hashes like Sign, with kilic as the herumi binding does not take hash tags.
+func (herumiMinSigBackend) hashToSignatureGroup(message, dst []byte) (Signature, error) {
+	M, err := herumiHashToG1(message, dst)
+	if err != nil {
+		return nil, err
+	}
+	return &HerumiMinSigSignature{M}, nil
+}
+
+func (herumiMinSigBackend) Verify(signature Signature, publicKey PublicKey, message, dst []byte, options ValidationOptions) bool {
+	_signature, err := toHerumiMinSigSignature(signature)
+	if err != nil {
+		return false
+	}
+	herumiPublicKeys, err := toHerumiMinSigPublicKeys([]PublicKey{publicKey})
+	if err != nil {
+		return false
+	}
+	if !herumiValidate(_signature.p, herumiPublicKeys, options) {
+		return false
+	}
+	return herumiMinSigVerifyPairing(_signature.p, herumiPublicKeys, [][]byte{message}, dst)
+}
+
+func (herumiMinSigBackend) FastAggregateVerify(signature Signature, publicKeys []PublicKey, message, dst []byte, options ValidationOptions) bool {
+	if len(publicKeys) == 0 {
+		return false
+	}
+	_signature, err := toHerumiMinSigSignature(signature)
+	if err != nil {
+		return false
+	}
+	herumiPublicKeys, err := toHerumiMinSigPublicKeys(publicKeys)
+	if err != nil {
+		return false
+	}
+	if !herumiValidate(_signature.p, herumiPublicKeys, options) {
+		return false
+	}
+	aggregated := new(herumiMinSigPublicKey)
+	for i := 0; i < len(herumiPublicKeys); i++ {
+		herumi.G2Add(aggregated, aggregated, &herumiPublicKeys[i])
+	}
+	return herumiMinSigVerifyPairing(_signature.p, []herumiMinSigPublicKey{*aggregated}, [][]byte{message}, dst)
+}
+
+func (herumiMinSigBackend) AggregateVerify(signature Signature, publicKeys []PublicKey, messages [][]byte, dst []byte, options ValidationOptions) bool {
+	size := len(publicKeys)
+	if size == 0 {
+		return false
+	}
+	if len(messages) != size {
+		return false
+	}
+	_signature, err := toHerumiMinSigSignature(signature)
+	if err != nil {
+		return false
+	}
+	herumiPublicKeys, err := toHerumiMinSigPublicKeys(publicKeys)
+	if err != nil {
+		return false
+	}
+	if !herumiValidate(_signature.p, herumiPublicKeys, options) {
+		return false
+	}
+	return herumiMinSigVerifyPairing(_signature.p, herumiPublicKeys, messages, dst)
+}
+
+func (herumiMinSigBackend) VerifyMultipleSignatures(sets []SignatureSet, dst []byte, options ValidationOptions) (bool, error) {
+	initHerumiMinSig()
+	size := len(sets)
+	// e(sum(r_i * sig_i), g2) == e(r_0 * H(m_0), pk_0) * ... * e(r_(n-1) * H(m_(n-1)), pk_(n-1))
+	g1s := make([]herumi.G1, size+1)
+	g2s := make([]herumi.G2, size+1)
+	sigs := make([]herumi.G1, size)
+	scalars := make([]herumi.Fr, size)
+	for i := 0; i < size; i++ {
+		signature, err := toHerumiMinSigSignature(sets[i].Signature)
+		if err != nil {
+			return false, err
+		}
+		publicKey, err := toHerumiMinSigPublicKey(sets[i].PublicKey)
+		if err != nil {
+			return false, err
+		}
+		if !herumiValidate(signature.p, []herumiMinSigPublicKey{*publicKey.p}, options) {
+			return false, nil
+		}
+		r, err := randBatchScalar()
+		if err != nil {
+			return false, err
+		}
+		// herumi expects little endian scalars
+		for j, k := 0, len(r)-1; j < k; j, k = j+1, k-1 {
+			r[j], r[k] = r[k], r[j]
+		}
+		if err := scalars[i].SetLittleEndian(r); err != nil {
+			return false, err
+		}
+		M, err := herumiHashToG1(sets[i].Message, dst)
+		if err != nil {
+			return false, err
+		}
+		herumi.G1Mul(&g1s[i], M, &scalars[i])
+		g2s[i] = *publicKey.p
+		sigs[i] = *signature.p
+	}
+	aggregated := new(herumi.G1)
+	herumi.G1MulVec(aggregated, sigs, scalars)
+	herumi.G1Neg(&g1s[size], aggregated)
+	g2s[size] = *herumiG2Generator
+	e := new(herumi.GT)
+	herumi.MillerLoopVec(e, g1s, g2s)
+	herumi.FinalExp(e, e)
+	return e.IsOne(), nil
+}
+
+func initHerumiMinSig() {
+	initHerumi()
+	herumiMinSigOnce.Do(func() {
+		g := kilic.NewG2()
+		herumiG2Generator = new(herumi.G2)
+		if err := herumiG2Generator.Deserialize(g.ToCompressed(g.One())); err != nil {
+			panic(err)
+		}
+	})
+}
+
+// herumiHashToG1 hashes message to G1 with given tag using kilic, as the herumi binding
+// only hashes to G2 with its own tag, and brings the point into herumi.
+func herumiHashToG1(message, dst []byte) (*herumi.G1, error) {
+	g := kilic.NewG1()
+	M, err := g.HashToCurve(message, dst)
+	if err != nil {
+		return nil, err
+	}
+	p := new(herumi.G1)
+	if err := p.Deserialize(g.ToCompressed(M)); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// herumiMinSigVerifyPairing checks e(signature, g2) == e(H(m_0), pk_0) * ... * e(H(m_(n-1)), pk_(n-1)).
+func herumiMinSigVerifyPairing(signature *herumiMinSigSignature, publicKeys []herumiMinSigPublicKey, messages [][]byte, dst []byte) bool {
+	initHerumiMinSig()
+	size := len(publicKeys)
+	g1s := make([]herumi.G1, size+1)
+	g2s := make([]herumi.G2, size+1)
+	for i := 0; i < size; i++ {
+		M, err := herumiHashToG1(messages[i], dst)
+		if err != nil {
+			return false
+		}
+		g1s[i] = *M
+		g2s[i] = publicKeys[i]
+	}
+	herumi.G1Neg(&g1s[size], signature)
+	g2s[size] = *herumiG2Generator
+	e := new(herumi.GT)
+	herumi.MillerLoopVec(e, g1s, g2s)
+	herumi.FinalExp(e, e)
+	return e.IsOne()
+}
+
+func (secretKey *HerumiMinSigSecretKey) Equal(other SecretKey) bool {
+	_other, err := toHerumiMinSigSecretKey(other)
+	if err != nil {
+		return false
+	}
+	return secretKey.s.IsEqual(_other.s)
+}
+
+func (secretKey *HerumiMinSigSecretKey) PublicKey() PublicKey {
+	initHerumiMinSig()
+	publicKey := new(herumiMinSigPublicKey)
+	herumi.G2Mul(publicKey, herumiG2Generator, herumi.CastFromSecretKey(secretKey.s))
+	return &HerumiMinSigPublicKey{publicKey}
+}
+
+func (secretKey *HerumiMinSigSecretKey) Sign(message []byte) Signature {
+	return herumiMinSigBackend{}.Sign(secretKey, message, minSigDST)
+}
+
+func (secretKey *HerumiMinSigSecretKey) ToBytes() []byte {
+	return secretKey.s.Serialize()
+}
+
+func (publicKey *HerumiMinSigPublicKey) FromBytes(compressed []byte) (PublicKey, error) {
+	if len(compressed) != MinSigPublicKeySize {
+		return nil, errPublicKeySize
+	}
+	p, err := herumiDecode[herumiMinSigPublicKey](compressed, true, publicKeyError)
+	if err != nil {
+		return nil, err
+	}
+	publicKey.p = p
+	return publicKey, nil
+}
+
+func (publicKey *HerumiMinSigPublicKey) ToBytes() []byte {
+	return publicKey.p.Serialize()
+}
+
+func (publicKey *HerumiMinSigPublicKey) FromUncompressed(uncompressed []byte) (PublicKey, error) {
+	if err := checkUncompressedPublicKey(uncompressed, UncompressedSignatureSize); err != nil {
+		return nil, err
+	}
+	p, err := herumiDecode[herumiMinSigPublicKey](uncompressed, true, publicKeyError)
+	if err != nil {
+		return nil, err
+	}
+	publicKey.p = p
+	return publicKey, nil
+}
+
+func (publicKey *HerumiMinSigPublicKey) ToUncompressed() []byte {
+	return publicKey.p.SerializeUncompressed()
+}
+
+func (publicKey *HerumiMinSigPublicKey) Equal(other PublicKey) bool {
+	_other, err := toHerumiMinSigPublicKey(other)
+	if err != nil {
+		return false
+	}
+	return publicKey.p.IsEqual(_other.p)
+}
+
+func (signature *HerumiMinSigSignature) FromBytes(compressed []byte) (Signature, error) {
+	if len(compressed) != MinSigSignatureSize {
+		return nil, errSignatureSize
+	}
+	p, err := herumiDecode[herumiMinSigSignature](compressed, false, signatureError)
+	if err != nil {
+		return nil, err
+	}
+	signature.p = p
+	return signature, nil
+}
+
+func (signature *HerumiMinSigSignature) ToBytes() []byte {
+	return signature.p.Serialize()
+}
+
+func (signature *HerumiMinSigSignature) FromUncompressed(uncompressed []byte) (Signature, error) {
+	if err := checkUncompressedSignature(uncompressed, UncompressedPublicKeySize); err != nil {
+		return nil, err
+	}
+	p, err := herumiDecode[herumiMinSigSignature](uncompressed, false, signatureError)
+	if err != nil {
+		return nil, err
+	}
+	signature.p = p
+	return signature, nil
+}
+
+func (signature *HerumiMinSigSignature) ToUncompressed() []byte {
+	return signature.p.SerializeUncompressed()
+}
+
+func (signature *HerumiMinSigSignature) Equal(other Signature) bool {
+	_other, err := toHerumiMinSigSignature(other)
+	if err != nil {
+		return false
+	}
+	return signature.p.IsEqual(_other.p)
+}
+
+func (signature *HerumiMinSigSignature) Verify(publicKey PublicKey, message []byte) bool {
+	return herumiMinSigBackend{}.Verify(signature, publicKey, message, minSigDST, defaultValidationOptions)
+}
+
+func (signature *HerumiMinSigSignature) FastAggregateVerify(publicKeys []PublicKey, message []byte) bool {
+	return herumiMinSigBackend{}.FastAggregateVerify(signature, publicKeys, message, minSigDST, defaultValidationOptions)
+}
+
+func (signature *HerumiMinSigSignature) AggregateVerify(publicKeys []PublicKey, messages [][]byte) bool {
+	return herumiMinSigBackend{}.AggregateVerify(signature, publicKeys, messages, minSigDST, defaultValidationOptions)
+}
+
+func herumiMinSigAggregateSignature(signatures []Signature) (Signature, error) {
+	points, err := convertPoints(signatures, herumiMinSigBackend{}, func(signature *HerumiMinSigSignature) *herumi.G1 { return signature.p })
+	if err != nil {
+		return nil, err
+	}
+	sum := aggregatePoints(newHerumiG1Sum(), points)
+	return &HerumiMinSigSignature{sum}, nil
+}
+
+func herumiMinSigAggregatePublicKey(publicKeys []PublicKey) (PublicKey, error) {
+	points, err := convertPoints(publicKeys, herumiMinSigBackend{}, func(publicKey *HerumiMinSigPublicKey) *herumi.G2 { return publicKey.p })
+	if err != nil {
+		return nil, err
+	}
+	sum := aggregatePoints(newHerumiG2Sum(), points)
+	return &HerumiMinSigPublicKey{sum}, nil
+}
+
+func toHerumiMinSigSecretKey(secretKey SecretKey) (*HerumiMinSigSecretKey, error) {
+	return convertTo[*HerumiMinSigSecretKey](secretKey, herumiMinSigBackend{})
+}
+
+func toHerumiMinSigPublicKey(publicKey PublicKey) (*HerumiMinSigPublicKey, error) {
+	return convertTo[*HerumiMinSigPublicKey](publicKey, herumiMinSigBackend{})
+}
+
+func toHerumiMinSigPublicKeys(publicKeys []PublicKey) ([]herumiMinSigPublicKey, error) {
+	return convertPoints(publicKeys, herumiMinSigBackend{}, func(publicKey *HerumiMinSigPublicKey) herumiMinSigPublicKey { return *publicKey.p })
+}
+
+func toHerumiMinSigSignature(signature Signature) (*HerumiMinSigSignature, error) {
+	return convertTo[*HerumiMinSigSignature](signature, herumiMinSigBackend{})
+}
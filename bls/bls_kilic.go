@@ -2,6 +2,7 @@ package cross_bls
 
 import (
 	"crypto/rand"
+	"math/big"
 
 	kilic "github.com/kilic/bls12-381"
 )
@@ -24,6 +25,251 @@ type KilicSignature struct {
 
 var kilicGroupOrder *kilicSecretKey
 
+type kilicBackend struct{}
+
+func (kilicBackend) Name() string {
+	return libKilic
+}
+
+func (kilicBackend) Variant() Variant {
+	return MinPublicKeySize
+}
+
+func (kilicBackend) initialize() {
+	initKilic()
+}
+
+func (kilicBackend) RandSecretKey() SecretKey {
+	return randKilicSecretKey()
+}
+
+func (kilicBackend) SecretKeyFromBytes(in []byte) (SecretKey, error) {
+	return kilicSecretKeyFromBytes(in)
+}
+
+func (kilicBackend) PublicKeyFromBytes(compressed []byte) (PublicKey, error) {
+	return new(KilicPublicKey).FromBytes(compressed)
+}
+
+func (kilicBackend) SignatureFromBytes(compressed []byte) (Signature, error) {
+	return new(KilicSignature).FromBytes(compressed)
+}
+
+func (kilicBackend) PublicKeyFromUncompressed(uncompressed []byte) (PublicKey, error) {
+	return new(KilicPublicKey).FromUncompressed(uncompressed)
+}
+
+func (kilicBackend) SignatureFromUncompressed(uncompressed []byte) (Signature, error) {
+	return new(KilicSignature).FromUncompressed(uncompressed)
+}
+
+// kilic checks the subgroup while decompressing, so only uncompressed encodings are decoded
+// without the subgroup check and compressed encodings are decoded as usual.
+
+func (kilicBackend) publicKeyFromBytesUnchecked(encoded []byte) (PublicKey, error) {
+	switch len(encoded) {
+	case PublicKeySize:
+		return new(KilicPublicKey).FromBytes(encoded)
+	case UncompressedPublicKeySize:
+	default:
+		return nil, errPublicKeySize
+	}
+	publicKey, err := kilic.NewG1().FromBytes(encoded)
+	if err != nil {
+		return nil, publicKeyError(encoded, false, kilicReason(err))
+	}
+	return &KilicPublicKey{publicKey}, nil
+}
+
+func (kilicBackend) signatureFromBytesUnchecked(encoded []byte) (Signature, error) {
+	switch len(encoded) {
+	case SignatureSize:
+		return new(KilicSignature).FromBytes(encoded)
+	case UncompressedSignatureSize:
+	default:
+		return nil, errSignatureSize
+	}
+	signature, err := kilic.NewG2().FromBytes(encoded)
+	if err != nil {
+		return nil, signatureError(encoded, true, kilicReason(err))
+	}
+	return &KilicSignature{signature}, nil
+}
+
+func (kilicBackend) AggregatePublicKeys(publicKeys []PublicKey) (PublicKey, error) {
+	return kilicAggregatePublicKey(publicKeys)
+}
+
+func (kilicBackend) AggregateSignatures(signatures []Signature) (Signature, error) {
+	return kilicAggregateSignature(signatures)
+}
+
+func (kilicBackend) mulPublicKey(publicKey PublicKey, scalar *big.Int) (PublicKey, error) {
+	_publicKey, err := toKilicPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	g := kilic.NewG1()
+	return &KilicPublicKey{g.MulScalarBig(g.New(), _publicKey.p, scalar)}, nil
+}
+
+func (kilicBackend) mulSignature(signature Signature, scalar *big.Int) (Signature, error) {
+	_signature, err := toKilicSignature(signature)
+	if err != nil {
+		return nil, err
+	}
+	g := kilic.NewG2()
+	return &KilicSignature{g.MulScalarBig(g.New(), _signature.p, scalar)}, nil
+}
+
+func (kilicBackend) NewPublicKeyAggregator() PublicKeyAggregator {
+	point := func(publicKey *KilicPublicKey) *kilicPublicKey { return publicKey.p }
+	to := func(sum *kilicPublicKey) *KilicPublicKey { return &KilicPublicKey{sum} }
+	return newPublicKeyAggregator(kilicBackend{}, newKilicSum[kilicPublicKey](kilic.NewG1()), point, to)
+}
+
+func (kilicBackend) NewSignatureAggregator() SignatureAggregator {
+	point := func(signature *KilicSignature) *kilicSignature { return signature.p }
+	to := func(sum *kilicSignature) *KilicSignature { return &KilicSignature{sum} }
+	return newSignatureAggregator(kilicBackend{}, newKilicSum[kilicSignature](kilic.NewG2()), point, to)
+}
+
+func (kilicBackend) Sign(secretKey SecretKey, message, dst []byte) Signature {
+	_secretKey, err := toKilicSecretKey(secretKey)
+	if err != nil {
+		return nil
+	}
+	g := kilic.NewG2()
+	M, err := g.HashToCurve(message, dst)
+	if err != nil {
+		return nil
+	}
+	signature := g.New()
+	g.MulScalar(signature, M, _secretKey.s)
+	return &KilicSignature{signature}
+}
+
+func (kilicBackend) hashToSignatureGroup(message, dst []byte) (Signature, error) {
+	M, err := kilic.NewG2().HashToCurve(message, dst)
+	if err != nil {
+		return nil, err
+	}
+	return &KilicSignature{M}, nil
+}
+
+func (kilicBackend) Verify(signature Signature, publicKey PublicKey, message, dst []byte, options ValidationOptions) bool {
+	_signature, err := toKilicSignature(signature)
+	if err != nil {
+		return false
+	}
+	_publicKey, err := toKilicPublicKey(publicKey)
+	if err != nil {
+		return false
+	}
+	e := kilic.NewEngine()
+	if !kilicValidate(e.G2, e.G1, _signature.p, []*kilicPublicKey{_publicKey.p}, options) {
+		return false
+	}
+	M, err := e.G2.HashToCurve(message, dst)
+	if err != nil {
+		return false
+	}
+	e.AddPair(_publicKey.p, M)
+	e.AddPairInv(e.G1.One(), _signature.p)
+	return e.Check()
+}
+
+func (kilicBackend) FastAggregateVerify(signature Signature, publicKeys []PublicKey, message, dst []byte, options ValidationOptions) bool {
+	if len(publicKeys) == 0 {
+		return false
+	}
+	_signature, err := toKilicSignature(signature)
+	if err != nil {
+		return false
+	}
+	kilicPublicKeys, err := toKilicPublicKeys(publicKeys)
+	if err != nil {
+		return false
+	}
+	e := kilic.NewEngine()
+	if !kilicValidate(e.G2, e.G1, _signature.p, kilicPublicKeys, options) {
+		return false
+	}
+	M, err := e.G2.HashToCurve(message, dst)
+	if err != nil {
+		return false
+	}
+	aggregated := aggregatePoints(newKilicSum[kilicPublicKey](e.G1), kilicPublicKeys)
+	e.AddPair((aggregated), M)
+	e.AddPairInv(e.G1.One(), _signature.p)
+	return e.Check()
+}
+
+func (kilicBackend) AggregateVerify(signature Signature, publicKeys []PublicKey, messages [][]byte, dst []byte, options ValidationOptions) bool {
+	if len(publicKeys) == 0 {
+		return false
+	}
+	if len(messages) != len(publicKeys) {
+		return false
+	}
+	_signature, err := toKilicSignature(signature)
+	if err != nil {
+		return false
+	}
+	kilicPublicKeys, err := toKilicPublicKeys(publicKeys)
+	if err != nil {
+		return false
+	}
+	e := kilic.NewEngine()
+	if !kilicValidate(e.G2, e.G1, _signature.p, kilicPublicKeys, options) {
+		return false
+	}
+	e.AddPairInv(e.G1.One(), _signature.p)
+	for i := 0; i < len(messages); i++ {
+		M, err := e.G2.HashToCurve(messages[i], dst)
+		if err != nil {
+			return false
+		}
+		e.AddPair(kilicPublicKeys[i], M)
+	}
+	return e.Check()
+}
+
+func (kilicBackend) VerifyMultipleSignatures(sets []SignatureSet, dst []byte, options ValidationOptions) (bool, error) {
+	e := kilic.NewEngine()
+	// e(g1, sum(r_i * sig_i)) == e(r_0 * pk_0, H(m_0)) * ... * e(r_(n-1) * pk_(n-1), H(m_(n-1)))
+	aggregated := e.G2.Zero()
+	for i := 0; i < len(sets); i++ {
+		signature, err := toKilicSignature(sets[i].Signature)
+		if err != nil {
+			return false, err
+		}
+		publicKey, err := toKilicPublicKey(sets[i].PublicKey)
+		if err != nil {
+			return false, err
+		}
+		if !kilicValidate(e.G2, e.G1, signature.p, []*kilicPublicKey{publicKey.p}, options) {
+			return false, nil
+		}
+		r, err := randBatchScalar()
+		if err != nil {
+			return false, err
+		}
+		scalar := new(kilic.Fr).FromBytes(r)
+		M, err := e.G2.HashToCurve(sets[i].Message, dst)
+		if err != nil {
+			return false, err
+		}
+		P, S := e.G1.New(), e.G2.New()
+		e.G1.MulScalar(P, publicKey.p, scalar)
+		e.G2.MulScalar(S, signature.p, scalar)
+		e.G2.Add(aggregated, aggregated, S)
+		e.AddPair(P, M)
+	}
+	e.AddPairInv(e.G1.One(), aggregated)
+	return e.Check(), nil
+}
+
 func randKilicSecretKey() SecretKey {
 	s, _ := new(kilic.Fr).Rand(rand.Reader)
 	return &KilicSecretKey{s}
@@ -35,13 +281,17 @@ func kilicSecretKeyFromBytes(in []byte) (SecretKey, error) {
 	}
 	s := new(kilicSecretKey).FromBytes(in)
 	if s.Cmp(kilicGroupOrder) != -1 {
-		return nil, errInvalidSecretKey
+		return nil, errSecretKeyNonCanonical
 	}
 	return &KilicSecretKey{s}, nil
 }
 
 func (secretKey *KilicSecretKey) Equal(other SecretKey) bool {
-	return secretKey.s.Equal(other.(*KilicSecretKey).s)
+	_other, err := toKilicSecretKey(other)
+	if err != nil {
+		return false
+	}
+	return secretKey.s.Equal(_other.s)
 }
 
 func (secretKey *KilicSecretKey) PublicKey() PublicKey {
@@ -52,14 +302,7 @@ func (secretKey *KilicSecretKey) PublicKey() PublicKey {
 }
 
 func (secretKey *KilicSecretKey) Sign(message []byte) Signature {
-	g := kilic.NewG2()
-	M, err := g.HashToCurve(message, dst)
-	if err != nil {
-		return nil
-	}
-	signature := g.New()
-	g.MulScalar(signature, M, secretKey.s)
-	return &KilicSignature{signature}
+	return kilicBackend{}.Sign(secretKey, message, dst)
 }
 
 func (secretKey *KilicSecretKey) ToBytes() []byte {
@@ -73,7 +316,7 @@ func (publicKey *KilicPublicKey) FromBytes(compressed []byte) (PublicKey, error)
 	g := kilic.NewG1()
 	kilicPublicKey, err := g.FromCompressed(compressed)
 	if err != nil {
-		return nil, err
+		return nil, publicKeyError(compressed, false, kilicReason(err))
 	}
 	publicKey.p = kilicPublicKey
 	return publicKey, nil
@@ -84,9 +327,33 @@ func (publicKey *KilicPublicKey) ToBytes() []byte {
 	return g.ToCompressed(publicKey.p)
 }
 
+func (publicKey *KilicPublicKey) FromUncompressed(uncompressed []byte) (PublicKey, error) {
+	if err := checkUncompressedPublicKey(uncompressed, UncompressedPublicKeySize); err != nil {
+		return nil, err
+	}
+	p, err := kilicFromUncompressed(kilic.NewG1(), uncompressed, false, publicKeyError)
+	if err != nil {
+		return nil, err
+	}
+	publicKey.p = p
+	return publicKey, nil
+}
+
+func (publicKey *KilicPublicKey) ToUncompressed() []byte {
+	g := kilic.NewG1()
+	if g.IsZero(publicKey.p) {
+		return infiniteUncompressed(UncompressedPublicKeySize)
+	}
+	return g.ToBytes(publicKey.p)
+}
+
 func (publicKey *KilicPublicKey) Equal(other PublicKey) bool {
+	_other, err := toKilicPublicKey(other)
+	if err != nil {
+		return false
+	}
 	g := kilic.NewG1()
-	return g.Equal(publicKey.p, (other.(*KilicPublicKey).p))
+	return g.Equal(publicKey.p, _other.p)
 }
 
 func (signature *KilicSignature) FromBytes(compressed []byte) (Signature, error) {
@@ -96,7 +363,7 @@ func (signature *KilicSignature) FromBytes(compressed []byte) (Signature, error)
 	g := kilic.NewG2()
 	kilicSignature, err := g.FromCompressed(compressed)
 	if err != nil {
-		return nil, err
+		return nil, signatureError(compressed, true, kilicReason(err))
 	}
 	signature.p = kilicSignature
 	return signature, nil
@@ -107,78 +374,153 @@ func (signature *KilicSignature) ToBytes() []byte {
 	return g.ToCompressed(signature.p)
 }
 
-func (signature *KilicSignature) Equal(other Signature) bool {
+func (signature *KilicSignature) FromUncompressed(uncompressed []byte) (Signature, error) {
+	if err := checkUncompressedSignature(uncompressed, UncompressedSignatureSize); err != nil {
+		return nil, err
+	}
+	p, err := kilicFromUncompressed(kilic.NewG2(), uncompressed, true, signatureError)
+	if err != nil {
+		return nil, err
+	}
+	signature.p = p
+	return signature, nil
+}
+
+func (signature *KilicSignature) ToUncompressed() []byte {
 	g := kilic.NewG2()
-	return g.Equal(signature.p, (other.(*KilicSignature).p))
+	if g.IsZero(signature.p) {
+		return infiniteUncompressed(UncompressedSignatureSize)
+	}
+	return g.ToBytes(signature.p)
 }
 
-func (signature *KilicSignature) Verify(publicKey PublicKey, message []byte) bool {
-	e := kilic.NewEngine()
-	M, err := e.G2.HashToCurve(message, dst)
+func (signature *KilicSignature) Equal(other Signature) bool {
+	_other, err := toKilicSignature(other)
 	if err != nil {
 		return false
 	}
-	e.AddPair((publicKey.(*KilicPublicKey).p), M)
-	e.AddPairInv(e.G1.One(), signature.p)
-	return e.Check()
+	g := kilic.NewG2()
+	return g.Equal(signature.p, _other.p)
+}
+
+func (signature *KilicSignature) Verify(publicKey PublicKey, message []byte) bool {
+	return kilicBackend{}.Verify(signature, publicKey, message, dst, defaultValidationOptions)
 }
 
 func (signature *KilicSignature) FastAggregateVerify(publicKeys []PublicKey, message []byte) bool {
-	e := kilic.NewEngine()
-	M, err := e.G2.HashToCurve(message, dst)
+	return kilicBackend{}.FastAggregateVerify(signature, publicKeys, message, dst, defaultValidationOptions)
+}
+
+func (signature *KilicSignature) AggregateVerify(publicKeys []PublicKey, messages [][]byte) bool {
+	return kilicBackend{}.AggregateVerify(signature, publicKeys, messages, dst, defaultValidationOptions)
+}
+
+func kilicAggregatePublicKey(publicKeys []PublicKey) (PublicKey, error) {
+	kilicPublicKeys, err := toKilicPublicKeys(publicKeys)
 	if err != nil {
-		return false
+		return nil, err
 	}
-	aggregated := kilicAggregatePublicKey(publicKeys, e.G1)
-	e.AddPair((aggregated.(*KilicPublicKey).p), M)
-	e.AddPairInv(e.G1.One(), signature.p)
-	return e.Check()
+	return &KilicPublicKey{aggregatePoints(newKilicSum[kilicPublicKey](kilic.NewG1()), kilicPublicKeys)}, nil
 }
 
-func (signature *KilicSignature) AggregateVerify(publicKeys []PublicKey, messages [][]byte) bool {
-	if len(publicKeys) == 0 {
-		return false
+func kilicAggregateSignature(signatures []Signature) (Signature, error) {
+	kilicSignatures, err := convertPoints(signatures, kilicBackend{}, func(signature *KilicSignature) *kilicSignature { return signature.p })
+	if err != nil {
+		return nil, err
 	}
-	if len(messages) != len(publicKeys) {
+	return &KilicSignature{aggregatePoints(newKilicSum[kilicSignature](kilic.NewG2()), kilicSignatures)}, nil
+}
+
+// kilicGroup is the arithmetic that kilic G1 and G2 share over points of type P.
+type kilicGroup[P any] interface {
+	Zero() *P
+	FromBytes(in []byte) (*P, error)
+	IsZero(p *P) bool
+	InCorrectSubgroup(p *P) bool
+	Add(r, p1, p2 *P) *P
+	Sub(c, a, b *P) *P
+}
+
+// kilicSum sums points of g.
+type kilicSum[P any, G kilicGroup[P]] struct {
+	g   G
+	sum *P
+}
+
+func newKilicSum[P any, G kilicGroup[P]](g G) pointSum[*P] {
+	return &kilicSum[P, G]{g, g.Zero()}
+}
+
+func (sum *kilicSum[P, G]) add(p *P) {
+	sum.g.Add(sum.sum, sum.sum, p)
+}
+
+func (sum *kilicSum[P, G]) sub(p *P) {
+	sum.g.Sub(sum.sum, sum.sum, p)
+}
+
+func (sum *kilicSum[P, G]) result() *P {
+	result := *sum.sum
+	return &result
+}
+
+// kilicValidate applies verification time checks selected in options to a signature in
+// signatureGroup and to public keys in publicKeyGroup.
+func kilicValidate[S, P any, GS kilicGroup[S], GP kilicGroup[P]](signatureGroup GS, publicKeyGroup GP, signature *S, publicKeys []*P, options ValidationOptions) bool {
+	if options.CheckSignatureSubgroup && !signatureGroup.InCorrectSubgroup(signature) {
 		return false
 	}
-	e := kilic.NewEngine()
-	e.AddPairInv(e.G1.One(), signature.p)
-	for i := 0; i < len(messages); i++ {
-		M, err := e.G2.HashToCurve(messages[i], dst)
-		if err != nil {
-			return false
+	if options.ValidatePublicKey {
+		for _, publicKey := range publicKeys {
+			if publicKeyGroup.IsZero(publicKey) || !publicKeyGroup.InCorrectSubgroup(publicKey) {
+				return false
+			}
 		}
-		e.AddPair(publicKeys[i].(*KilicPublicKey).p, M)
 	}
-	return e.Check()
-
+	return true
 }
 
-func kilicAggregatePublicKey(publicKeys []PublicKey, g *kilic.G1) PublicKey {
-	if g == nil {
-		g = kilic.NewG1()
+// kilicFromUncompressed decodes an uncompressed point of g that is in the subgroup and is not
+// the point at infinity. fail returns the error of a rejected point.
+func kilicFromUncompressed[P any, G kilicGroup[P]](g G, uncompressed []byte, onG2 bool, fail pointError) (*P, error) {
+	p, err := g.FromBytes(uncompressed)
+	if err != nil {
+		return nil, fail(uncompressed, onG2, kilicReason(err))
 	}
-	if len(publicKeys) == 0 {
-		return &KilicPublicKey{g.Zero()}
+	if g.IsZero(p) {
+		return nil, fail(uncompressed, onG2, nil)
 	}
-	aggregated := new(kilicPublicKey).Set(publicKeys[0].(*KilicPublicKey).p)
-	for i := 1; i < len(publicKeys); i++ {
-		g.Add(aggregated, aggregated, publicKeys[i].(*KilicPublicKey).p)
+	if !g.InCorrectSubgroup(p) {
+		return nil, fail(uncompressed, onG2, ErrNotInSubgroup)
 	}
-	return &KilicPublicKey{aggregated}
+	return p, nil
 }
 
-func kilicAggregateSignature(signatures []Signature, g *kilic.G2) Signature {
-	if g == nil {
-		g = kilic.NewG2()
-	}
-	if len(signatures) == 0 {
-		return &KilicSignature{g.Zero()}
-	}
-	aggregated := new(kilicSignature).Set(signatures[0].(*KilicSignature).p)
-	for i := 1; i < len(signatures); i++ {
-		g.Add(aggregated, aggregated, signatures[i].(*KilicSignature).p)
-	}
-	return &KilicSignature{aggregated}
+// kilicReasons maps the failures of kilic decoding, which checks on curve and subgroup
+// after the rules of the encoding, to errors.
+var kilicReasons = map[string]error{
+	"point is not on curve":            ErrNotOnCurve,
+	"point is not on correct subgroup": ErrNotInSubgroup,
+}
+
+// kilicReason returns the failure that kilic reported while decoding a point,
+// or nil if it is a rule of the encoding that is classified independently.
+func kilicReason(err error) error {
+	return kilicReasons[err.Error()]
+}
+
+func toKilicSecretKey(secretKey SecretKey) (*KilicSecretKey, error) {
+	return convertTo[*KilicSecretKey](secretKey, kilicBackend{})
+}
+
+func toKilicPublicKey(publicKey PublicKey) (*KilicPublicKey, error) {
+	return convertTo[*KilicPublicKey](publicKey, kilicBackend{})
+}
+
+func toKilicPublicKeys(publicKeys []PublicKey) ([]*kilicPublicKey, error) {
+	return convertPoints(publicKeys, kilicBackend{}, func(publicKey *KilicPublicKey) *kilicPublicKey { return publicKey.p })
+}
+
+func toKilicSignature(signature Signature) (*KilicSignature, error) {
+	return convertTo[*KilicSignature](signature, kilicBackend{})
 }
@@ -0,0 +1,432 @@
+package cross_bls
+
+import (
+	"math/big"
+
+	kilic "github.com/kilic/bls12-381"
+)
+
+type kilicMinSigPublicKey = kilic.PointG2
+type kilicMinSigSignature = kilic.PointG1
+
+type KilicMinSigPublicKey struct {
+	p *kilicMinSigPublicKey
+}
+
+type KilicMinSigSecretKey struct {
+	s *kilicSecretKey
+}
+
+type KilicMinSigSignature struct {
+	p *kilicMinSigSignature
+}
+
+type kilicMinSigBackend struct{}
+
+func (kilicMinSigBackend) Name() string {
+	return libKilicMinSig
+}
+
+func (kilicMinSigBackend) Variant() Variant {
+	return MinSignatureSize
+}
+
+func (kilicMinSigBackend) initialize() {
+	initKilic()
+}
+
+func (kilicMinSigBackend) RandSecretKey() SecretKey {
+	return &KilicMinSigSecretKey{randKilicSecretKey().(*KilicSecretKey).s}
+}
+
+func (kilicMinSigBackend) SecretKeyFromBytes(in []byte) (SecretKey, error) {
+	secretKey, err := kilicSecretKeyFromBytes(in)
+	if err != nil {
+		return nil, err
+	}
+	return &KilicMinSigSecretKey{secretKey.(*KilicSecretKey).s}, nil
+}
+
+func (kilicMinSigBackend) PublicKeyFromBytes(compressed []byte) (PublicKey, error) {
+	return new(KilicMinSigPublicKey).FromBytes(compressed)
+}
+
+func (kilicMinSigBackend) SignatureFromBytes(compressed []byte) (Signature, error) {
+	return new(KilicMinSigSignature).FromBytes(compressed)
+}
+
+func (kilicMinSigBackend) PublicKeyFromUncompressed(uncompressed []byte) (PublicKey, error) {
+	return new(KilicMinSigPublicKey).FromUncompressed(uncompressed)
+}
+
+func (kilicMinSigBackend) SignatureFromUncompressed(uncompressed []byte) (Signature, error) {
+	return new(KilicMinSigSignature).FromUncompressed(uncompressed)
+}
+
+// kilic checks the subgroup while decompressing, so only uncompressed encodings are decoded
+// without the subgroup check and compressed encodings are decoded as usual.
+
+func (kilicMinSigBackend) publicKeyFromBytesUnchecked(encoded []byte) (PublicKey, error) {
+	switch len(encoded) {
+	case MinSigPublicKeySize:
+		return new(KilicMinSigPublicKey).FromBytes(encoded)
+	case UncompressedSignatureSize:
+	default:
+		return nil, errPublicKeySize
+	}
+	publicKey, err := kilic.NewG2().FromBytes(encoded)
+	if err != nil {
+		return nil, publicKeyError(encoded, true, kilicReason(err))
+	}
+	return &KilicMinSigPublicKey{publicKey}, nil
+}
+
+func (kilicMinSigBackend) signatureFromBytesUnchecked(encoded []byte) (Signature, error) {
+	switch len(encoded) {
+	case MinSigSignatureSize:
+		return new(KilicMinSigSignature).FromBytes(encoded)
+	case UncompressedPublicKeySize:
+	default:
+		return nil, errSignatureSize
+	}
+	signature, err := kilic.NewG1().FromBytes(encoded)
+	if err != nil {
+		return nil, signatureError(encoded, false, kilicReason(err))
+	}
+	return &KilicMinSigSignature{signature}, nil
+}
+
+func (kilicMinSigBackend) AggregatePublicKeys(publicKeys []PublicKey) (PublicKey, error) {
+	return kilicMinSigAggregatePublicKey(publicKeys)
+}
+
+func (kilicMinSigBackend) AggregateSignatures(signatures []Signature) (Signature, error) {
+	return kilicMinSigAggregateSignature(signatures)
+}
+
+func (kilicMinSigBackend) mulPublicKey(publicKey PublicKey, scalar *big.Int) (PublicKey, error) {
+	_publicKey, err := toKilicMinSigPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	g := kilic.NewG2()
+	return &KilicMinSigPublicKey{g.MulScalarBig(g.New(), _publicKey.p, scalar)}, nil
+}
+
+func (kilicMinSigBackend) mulSignature(signature Signature, scalar *big.Int) (Signature, error) {
+	_signature, err := toKilicMinSigSignature(signature)
+	if err != nil {
+		return nil, err
+	}
+	g := kilic.NewG1()
+	return &KilicMinSigSignature{g.MulScalarBig(g.New(), _signature.p, scalar)}, nil
+}
+
+func (kilicMinSigBackend) NewPublicKeyAggregator() PublicKeyAggregator {
+	point := func(publicKey *KilicMinSigPublicKey) *kilicMinSigPublicKey { return publicKey.p }
+	to := func(sum *kilicMinSigPublicKey) *KilicMinSigPublicKey { return &KilicMinSigPublicKey{sum} }
+	return newPublicKeyAggregator(kilicMinSigBackend{}, newKilicSum[kilicMinSigPublicKey](kilic.NewG2()), point, to)
+}
+
+func (kilicMinSigBackend) NewSignatureAggregator() SignatureAggregator {
+	point := func(signature *KilicMinSigSignature) *kilicMinSigSignature { return signature.p }
+	to := func(sum *kilicMinSigSignature) *KilicMinSigSignature { return &KilicMinSigSignature{sum} }
+	return newSignatureAggregator(kilicMinSigBackend{}, newKilicSum[kilicMinSigSignature](kilic.NewG1()), point, to)
+}
+
+func (kilicMinSigBackend) Sign(secretKey SecretKey, message, dst []byte) Signature {
+	_secretKey, err := toKilicMinSigSecretKey(secretKey)
+	if err != nil {
+		return nil
+	}
+	g := kilic.NewG1()
+	M, err := g.HashToCurve(message, dst)
+	if err != nil {
+		return nil
+	}
+	signature := g.New()
+	g.MulScalar(signature, M, _secretKey.s)
+	return &KilicMinSigSignature{signature}
+}
+
+func (kilicMinSigBackend) hashToSignatureGroup(message, dst []byte) (Signature, error) {
+	M, err := kilic.NewG1().HashToCurve(message, dst)
+	if err != nil {
+		return nil, err
+	}
+	return &KilicMinSigSignature{M}, nil
+}
+
+func (kilicMinSigBackend) Verify(signature Signature, publicKey PublicKey, message, dst []byte, options ValidationOptions) bool {
+	_signature, err := toKilicMinSigSignature(signature)
+	if err != nil {
+		return false
+	}
+	_publicKey, err := toKilicMinSigPublicKey(publicKey)
+	if err != nil {
+		return false
+	}
+	e := kilic.NewEngine()
+	if !kilicValidate(e.G1, e.G2, _signature.p, []*kilicMinSigPublicKey{_publicKey.p}, options) {
+		return false
+	}
+	M, err := e.G1.HashToCurve(message, dst)
+	if err != nil {
+		return false
+	}
+	e.AddPair(M, _publicKey.p)
+	e.AddPairInv(_signature.p, e.G2.One())
+	return e.Check()
+}
+
+func (kilicMinSigBackend) FastAggregateVerify(signature Signature, publicKeys []PublicKey, message, dst []byte, options ValidationOptions) bool {
+	if len(publicKeys) == 0 {
+		return false
+	}
+	_signature, err := toKilicMinSigSignature(signature)
+	if err != nil {
+		return false
+	}
+	kilicPublicKeys, err := toKilicMinSigPublicKeys(publicKeys)
+	if err != nil {
+		return false
+	}
+	e := kilic.NewEngine()
+	if !kilicValidate(e.G1, e.G2, _signature.p, kilicPublicKeys, options) {
+		return false
+	}
+	M, err := e.G1.HashToCurve(message, dst)
+	if err != nil {
+		return false
+	}
+	aggregated := aggregatePoints(newKilicSum[kilicMinSigPublicKey](e.G2), kilicPublicKeys)
+	e.AddPair(M, aggregated)
+	e.AddPairInv(_signature.p, e.G2.One())
+	return e.Check()
+}
+
+func (kilicMinSigBackend) AggregateVerify(signature Signature, publicKeys []PublicKey, messages [][]byte, dst []byte, options ValidationOptions) bool {
+	if len(publicKeys) == 0 {
+		return false
+	}
+	if len(messages) != len(publicKeys) {
+		return false
+	}
+	_signature, err := toKilicMinSigSignature(signature)
+	if err != nil {
+		return false
+	}
+	kilicPublicKeys, err := toKilicMinSigPublicKeys(publicKeys)
+	if err != nil {
+		return false
+	}
+	e := kilic.NewEngine()
+	if !kilicValidate(e.G1, e.G2, _signature.p, kilicPublicKeys, options) {
+		return false
+	}
+	e.AddPairInv(_signature.p, e.G2.One())
+	for i := 0; i < len(messages); i++ {
+		M, err := e.G1.HashToCurve(messages[i], dst)
+		if err != nil {
+			return false
+		}
+		e.AddPair(M, kilicPublicKeys[i])
+	}
+	return e.Check()
+}
+
+func (kilicMinSigBackend) VerifyMultipleSignatures(sets []SignatureSet, dst []byte, options ValidationOptions) (bool, error) {
+	e := kilic.NewEngine()
+	// e(sum(r_i * sig_i), g2) == e(r_0 * H(m_0), pk_0) * ... * e(r_(n-1) * H(m_(n-1)), pk_(n-1))
+	aggregated := e.G1.Zero()
+	for i := 0; i < len(sets); i++ {
+		signature, err := toKilicMinSigSignature(sets[i].Signature)
+		if err != nil {
+			return false, err
+		}
+		publicKey, err := toKilicMinSigPublicKey(sets[i].PublicKey)
+		if err != nil {
+			return false, err
+		}
+		if !kilicValidate(e.G1, e.G2, signature.p, []*kilicMinSigPublicKey{publicKey.p}, options) {
+			return false, nil
+		}
+		r, err := randBatchScalar()
+		if err != nil {
+			return false, err
+		}
+		scalar := new(kilic.Fr).FromBytes(r)
+		M, err := e.G1.HashToCurve(sets[i].Message, dst)
+		if err != nil {
+			return false, err
+		}
+		S := e.G1.New()
+		e.G1.MulScalar(M, M, scalar)
+		e.G1.MulScalar(S, signature.p, scalar)
+		e.G1.Add(aggregated, aggregated, S)
+		e.AddPair(M, publicKey.p)
+	}
+	e.AddPairInv(aggregated, e.G2.One())
+	return e.Check(), nil
+}
+
+func (secretKey *KilicMinSigSecretKey) Equal(other SecretKey) bool {
+	_other, err := toKilicMinSigSecretKey(other)
+	if err != nil {
+		return false
+	}
+	return secretKey.s.Equal(_other.s)
+}
+
+func (secretKey *KilicMinSigSecretKey) PublicKey() PublicKey {
+	g := kilic.NewG2()
+	publicKey := g.New()
+	g.MulScalar(publicKey, g.One(), secretKey.s)
+	return &KilicMinSigPublicKey{publicKey}
+}
+
+func (secretKey *KilicMinSigSecretKey) Sign(message []byte) Signature {
+	return kilicMinSigBackend{}.Sign(secretKey, message, minSigDST)
+}
+
+func (secretKey *KilicMinSigSecretKey) ToBytes() []byte {
+	return secretKey.s.ToBytes()
+}
+
+func (publicKey *KilicMinSigPublicKey) FromBytes(compressed []byte) (PublicKey, error) {
+	if len(compressed) != MinSigPublicKeySize {
+		return nil, errPublicKeySize
+	}
+	g := kilic.NewG2()
+	kilicPublicKey, err := g.FromCompressed(compressed)
+	if err != nil {
+		return nil, publicKeyError(compressed, true, kilicReason(err))
+	}
+	publicKey.p = kilicPublicKey
+	return publicKey, nil
+}
+
+func (publicKey *KilicMinSigPublicKey) ToBytes() []byte {
+	g := kilic.NewG2()
+	return g.ToCompressed(publicKey.p)
+}
+
+func (publicKey *KilicMinSigPublicKey) FromUncompressed(uncompressed []byte) (PublicKey, error) {
+	if err := checkUncompressedPublicKey(uncompressed, UncompressedSignatureSize); err != nil {
+		return nil, err
+	}
+	p, err := kilicFromUncompressed(kilic.NewG2(), uncompressed, true, publicKeyError)
+	if err != nil {
+		return nil, err
+	}
+	publicKey.p = p
+	return publicKey, nil
+}
+
+func (publicKey *KilicMinSigPublicKey) ToUncompressed() []byte {
+	g := kilic.NewG2()
+	if g.IsZero(publicKey.p) {
+		return infiniteUncompressed(UncompressedSignatureSize)
+	}
+	return g.ToBytes(publicKey.p)
+}
+
+func (publicKey *KilicMinSigPublicKey) Equal(other PublicKey) bool {
+	_other, err := toKilicMinSigPublicKey(other)
+	if err != nil {
+		return false
+	}
+	g := kilic.NewG2()
+	return g.Equal(publicKey.p, _other.p)
+}
+
+func (signature *KilicMinSigSignature) FromBytes(compressed []byte) (Signature, error) {
+	if len(compressed) != MinSigSignatureSize {
+		return nil, errSignatureSize
+	}
+	g := kilic.NewG1()
+	kilicSignature, err := g.FromCompressed(compressed)
+	if err != nil {
+		return nil, signatureError(compressed, false, kilicReason(err))
+	}
+	signature.p = kilicSignature
+	return signature, nil
+}
+
+func (signature *KilicMinSigSignature) ToBytes() []byte {
+	g := kilic.NewG1()
+	return g.ToCompressed(signature.p)
+}
+
+func (signature *KilicMinSigSignature) FromUncompressed(uncompressed []byte) (Signature, error) {
+	if err := checkUncompressedSignature(uncompressed, UncompressedPublicKeySize); err != nil {
+		return nil, err
+	}
+	p, err := kilicFromUncompressed(kilic.NewG1(), uncompressed, false, signatureError)
+	if err != nil {
+		return nil, err
+	}
+	signature.p = p
+	return signature, nil
+}
+
+func (signature *KilicMinSigSignature) ToUncompressed() []byte {
+	g := kilic.NewG1()
+	if g.IsZero(signature.p) {
+		return infiniteUncompressed(UncompressedPublicKeySize)
+	}
+	return g.ToBytes(signature.p)
+}
+
+func (signature *KilicMinSigSignature) Equal(other Signature) bool {
+	_other, err := toKilicMinSigSignature(other)
+	if err != nil {
+		return false
+	}
+	g := kilic.NewG1()
+	return g.Equal(signature.p, _other.p)
+}
+
+func (signature *KilicMinSigSignature) Verify(publicKey PublicKey, message []byte) bool {
+	return kilicMinSigBackend{}.Verify(signature, publicKey, message, minSigDST, defaultValidationOptions)
+}
+
+func (signature *KilicMinSigSignature) FastAggregateVerify(publicKeys []PublicKey, message []byte) bool {
+	return kilicMinSigBackend{}.FastAggregateVerify(signature, publicKeys, message, minSigDST, defaultValidationOptions)
+}
+
+func (signature *KilicMinSigSignature) AggregateVerify(publicKeys []PublicKey, messages [][]byte) bool {
+	return kilicMinSigBackend{}.AggregateVerify(signature, publicKeys, messages, minSigDST, defaultValidationOptions)
+}
+
+func kilicMinSigAggregatePublicKey(publicKeys []PublicKey) (PublicKey, error) {
+	kilicPublicKeys, err := toKilicMinSigPublicKeys(publicKeys)
+	if err != nil {
+		return nil, err
+	}
+	return &KilicMinSigPublicKey{aggregatePoints(newKilicSum[kilicMinSigPublicKey](kilic.NewG2()), kilicPublicKeys)}, nil
+}
+
+func kilicMinSigAggregateSignature(signatures []Signature) (Signature, error) {
+	kilicSignatures, err := convertPoints(signatures, kilicMinSigBackend{}, func(signature *KilicMinSigSignature) *kilicMinSigSignature { return signature.p })
+	if err != nil {
+		return nil, err
+	}
+	return &KilicMinSigSignature{aggregatePoints(newKilicSum[kilicMinSigSignature](kilic.NewG1()), kilicSignatures)}, nil
+}
+
+func toKilicMinSigSecretKey(secretKey SecretKey) (*KilicMinSigSecretKey, error) {
+	return convertTo[*KilicMinSigSecretKey](secretKey, kilicMinSigBackend{})
+}
+
+func toKilicMinSigPublicKey(publicKey PublicKey) (*KilicMinSigPublicKey, error) {
+	return convertTo[*KilicMinSigPublicKey](publicKey, kilicMinSigBackend{})
+}
+
+func toKilicMinSigPublicKeys(publicKeys []PublicKey) ([]*kilicMinSigPublicKey, error) {
+	return convertPoints(publicKeys, kilicMinSigBackend{}, func(publicKey *KilicMinSigPublicKey) *kilicMinSigPublicKey { return publicKey.p })
+}
+
+func toKilicMinSigSignature(signature Signature) (*KilicMinSigSignature, error) {
+	return convertTo[*KilicMinSigSignature](signature, kilicMinSigBackend{})
+}
@@ -4,10 +4,15 @@ import (
 	"bytes"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"flag"
 	"fmt"
+	"math/big"
 	"os"
 	"testing"
+
+	kilic "github.com/kilic/bls12-381"
+	blst "github.com/supranational/blst/bindings/go"
 )
 
 func randPublicKey() PublicKey {
@@ -82,6 +87,1696 @@ func TestCross(t *testing.T) {
 	if !bytes.Equal(signatureBytes, kilicSignature.ToBytes()) {
 		t.Fatal("kilic signature")
 	}
+
+	blstSuite := NewSuite(blstBackend{}, dst, defaultValidationOptions)
+	herumiSuite := NewSuite(herumiBackend{}, dst, defaultValidationOptions)
+	kilicSuite := NewSuite(kilicBackend{}, dst, defaultValidationOptions)
+	blstProof := blstSuite.PopProve(blstSecretKey)
+	herumiProof := herumiSuite.PopProve(herumiSecretKey)
+	kilicProof := kilicSuite.PopProve(kilicSecretKey)
+
+	proofBytes := blstProof.ToBytes()
+	if !bytes.Equal(proofBytes, herumiProof.ToBytes()) {
+		t.Fatal("herumi proof of possession")
+	}
+	if !bytes.Equal(proofBytes, kilicProof.ToBytes()) {
+		t.Fatal("kilic proof of possession")
+	}
+	if bytes.Equal(proofBytes, blstSecretKey.Sign(publicKeyBytes).ToBytes()) {
+		t.Fatal("proof of possession must be domain separated")
+	}
+	if !blstSuite.PopVerify(blstPublicKey, blstProof) {
+		t.Fatal("blst proof of possession must be verified")
+	}
+	if !herumiSuite.PopVerify(herumiPublicKey, herumiProof) {
+		t.Fatal("herumi proof of possession must be verified")
+	}
+	if !kilicSuite.PopVerify(kilicPublicKey, kilicProof) {
+		t.Fatal("kilic proof of possession must be verified")
+	}
+}
+
+func TestPopVerify(t *testing.T) {
+	secretKey := RandSecretKey()
+	publicKey := secretKey.PublicKey()
+	proof := PopProve(secretKey)
+	if !PopVerify(publicKey, proof) {
+		t.Fatalf("must be verified")
+	}
+	if PopVerify(randPublicKey(), proof) {
+		t.Fatalf("must not be verified with another public key")
+	}
+	if PopVerify(publicKey, secretKey.Sign(publicKey.ToBytes())) {
+		t.Fatalf("signature must not be verified as proof")
+	}
+	if PopVerify(publicKey, nil) || PopVerify(nil, proof) {
+		t.Fatalf("nil values must not be verified")
+	}
+}
+
+type testBackend struct {
+	Backend
+}
+
+func (testBackend) Name() string {
+	return "test"
+}
+
+func (b testBackend) initialize() {
+	initBackend(b.Backend)
+}
+
+func TestBackendRegistry(t *testing.T) {
+	for _, name := range []string{libHerumi, libBLST, libKilic} {
+		backend, err := Lookup(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if backend.Name() != name {
+			t.Fatalf("bad backend name, have: %s, want: %s", backend.Name(), name)
+		}
+	}
+	if _, err := Lookup("unknown"); !errors.Is(err, ErrUnknownBackend) {
+		t.Fatalf("unknown backend must fail")
+	}
+	if err := UseBackend("unknown"); !errors.Is(err, ErrUnknownBackend) {
+		t.Fatalf("unknown backend must not be used")
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("registering a backend twice must panic")
+			}
+		}()
+		Register(kilicBackend{})
+	}()
+
+	kilic, err := Lookup(libKilic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	Register(testBackend{kilic})
+	previousLibrary, previousSuite := library, currentSuite()
+	defer func() {
+		unregister("test")
+		library = previousLibrary
+		defaultSuite.Store(previousSuite)
+	}()
+	if err := UseBackend("test"); err != nil {
+		t.Fatal(err)
+	}
+	secretKey := RandSecretKey()
+	if _, ok := secretKey.(*KilicSecretKey); !ok {
+		t.Fatalf("registered backend is not used")
+	}
+	if _, err := SecretKeyFromBytes(secretKey.ToBytes()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUseMinSigBackend(t *testing.T) {
+	previousLibrary, previousSuite := library, currentSuite()
+	defer func() {
+		library = previousLibrary
+		defaultSuite.Store(previousSuite)
+	}()
+	message := []byte("test")
+	for _, name := range []string{libHerumiMinSig, libBLSTMinSig, libKilicMinSig} {
+		if err := UseBackend(name); err != nil {
+			t.Fatal(err)
+		}
+		secretKey := RandSecretKey()
+		publicKey := secretKey.PublicKey()
+		signature := secretKey.Sign(message)
+		if !signature.Verify(publicKey, message) {
+			t.Fatalf("%s: must be verified", name)
+		}
+		if ok, err := VerifyMultipleSignatures([]SignatureSet{{publicKey, message, signature}}); err != nil || !ok {
+			t.Fatalf("%s: signature sets must be verified", name)
+		}
+		if !EthFastAggregateVerify(signature, []PublicKey{publicKey}, message) {
+			t.Fatalf("%s: eth fast aggregate must be verified", name)
+		}
+		if !PopVerify(publicKey, PopProve(secretKey)) {
+			t.Fatalf("%s: proof of possession must be verified", name)
+		}
+	}
+}
+
+func newSuites(t *testing.T, dst []byte, options ValidationOptions) []*Suite {
+	suites := []*Suite{}
+	for _, name := range []string{libHerumi, libBLST, libKilic} {
+		backend, err := Lookup(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		suites = append(suites, NewSuite(backend, dst, options))
+	}
+	return suites
+}
+
+func TestSuiteCross(t *testing.T) {
+	customDST := []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_NUL_")
+	options := ValidationOptions{CheckSignatureSubgroup: true, ValidatePublicKey: true}
+	for _, _dst := range [][]byte{dst, customDST} {
+		suites := newSuites(t, _dst, options)
+		secretKeyBytes := randKilicSecretKey().ToBytes()
+		for _, message := range [][]byte{[]byte("test"), {}} {
+			var expected []byte
+			for _, suite := range suites {
+				secretKey, err := suite.SecretKeyFromBytes(secretKeyBytes)
+				if err != nil {
+					t.Fatal(err)
+				}
+				signature := suite.Sign(secretKey, message)
+				if expected == nil {
+					expected = signature.ToBytes()
+				} else if !bytes.Equal(expected, signature.ToBytes()) {
+					t.Fatalf("%s signature", suite.Backend().Name())
+				}
+				publicKey := secretKey.PublicKey()
+				if !suite.Verify(signature, publicKey, message) {
+					t.Fatalf("%s must be verified", suite.Backend().Name())
+				}
+				if suite.Verify(signature, publicKey, []byte("other")) {
+					t.Fatalf("%s must not be verified", suite.Backend().Name())
+				}
+				if !suite.FastAggregateVerify(signature, []PublicKey{publicKey}, message) {
+					t.Fatalf("%s must be verified", suite.Backend().Name())
+				}
+				if !suite.AggregateVerify(signature, []PublicKey{publicKey}, [][]byte{message}) {
+					t.Fatalf("%s must be verified", suite.Backend().Name())
+				}
+				other := NewSuite(suite.Backend(), []byte("BLS_SIG_OTHER_TAG"), options)
+				if other.Verify(signature, publicKey, message) {
+					t.Fatalf("%s must not be verified with another tag", suite.Backend().Name())
+				}
+			}
+		}
+	}
+}
+
+func newCiphersuites(t *testing.T, variant Variant, ciphersuite Ciphersuite, options ValidationOptions) []*Suite {
+	names := []string{libHerumi, libBLST, libKilic}
+	if variant == MinSignatureSize {
+		names = []string{libHerumiMinSig, libBLSTMinSig, libKilicMinSig}
+	}
+	suites := []*Suite{}
+	for _, name := range names {
+		backend, err := Lookup(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		suite, err := NewCiphersuite(backend, ciphersuite, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		suites = append(suites, suite)
+	}
+	return suites
+}
+
+func TestCiphersuiteCross(t *testing.T) {
+	const nPublicKeys = 4
+	options := ValidationOptions{CheckSignatureSubgroup: true, ValidatePublicKey: true}
+	secretKeys := make([]SecretKey, nPublicKeys)
+	publicKeys := make([]PublicKey, nPublicKeys)
+	messages := make([][]byte, nPublicKeys)
+	for i := 0; i < nPublicKeys; i++ {
+		secretKeys[i] = randKilicSecretKey()
+		publicKeys[i] = secretKeys[i].PublicKey()
+		messages[i] = []byte(fmt.Sprintf("test %d", i))
+	}
+	message := []byte("test")
+	duplicates := [][]byte{message, message, message, message}
+	seen := map[string]Ciphersuite{}
+	for _, ciphersuite := range []Ciphersuite{Basic, Aug, PoP} {
+		var expected []byte
+		for _, suite := range newCiphersuites(t, MinPublicKeySize, ciphersuite, options) {
+			name := ciphersuite.String() + " " + suite.Backend().Name()
+			if !bytes.Equal(suite.DST(), ciphersuite.DST(MinPublicKeySize)) {
+				t.Fatalf("%s: tag", name)
+			}
+			signature := suite.Sign(secretKeys[0], message)
+			if expected == nil {
+				expected = signature.ToBytes()
+				if _, ok := seen[string(expected)]; ok {
+					t.Fatalf("%s: signature must be ciphersuite specific", name)
+				}
+				seen[string(expected)] = ciphersuite
+			} else if !bytes.Equal(expected, signature.ToBytes()) {
+				t.Fatalf("%s: signature", name)
+			}
+			if !suite.Verify(signature, publicKeys[0], message) {
+				t.Fatalf("%s: must be verified", name)
+			}
+			if suite.Verify(signature, publicKeys[1], message) {
+				t.Fatalf("%s: must not be verified", name)
+			}
+			if suite.FastAggregateVerify(signature, publicKeys[:1], message) != (ciphersuite == PoP) {
+				t.Fatalf("%s: fast aggregate verify is only defined in PoP", name)
+			}
+			// Aug signs public key and message under its own tag
+			if ciphersuite == Aug {
+				raw := NewSuite(suite.Backend(), Aug.DST(MinPublicKeySize), options).Sign(secretKeys[0], append(publicKeys[0].ToBytes(), message...))
+				if !raw.Equal(signature) {
+					t.Fatalf("%s: message must be augmented", name)
+				}
+			}
+
+			signatures := make([]Signature, nPublicKeys)
+			duplicateSignatures := make([]Signature, nPublicKeys)
+			sets := make([]SignatureSet, nPublicKeys)
+			for i := 0; i < nPublicKeys; i++ {
+				signatures[i] = suite.Sign(secretKeys[i], messages[i])
+				duplicateSignatures[i] = suite.Sign(secretKeys[i], message)
+				sets[i] = SignatureSet{publicKeys[i], messages[i], signatures[i]}
+			}
+			aggregated, err := suite.AggregateSignatures(signatures)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !suite.AggregateVerify(aggregated, publicKeys, messages) {
+				t.Fatalf("%s: must be verified", name)
+			}
+			aggregated, err = suite.AggregateSignatures(duplicateSignatures)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ciphersuite == Basic && suite.AggregateVerify(aggregated, publicKeys, duplicates) {
+				t.Fatalf("%s: duplicate messages must be rejected in Basic", name)
+			}
+			if ok, err := suite.VerifyMultipleSignatures(sets); err != nil || !ok {
+				t.Fatalf("%s: signature sets must be verified", name)
+			}
+		}
+	}
+	if _, err := NewCiphersuite(kilicBackend{}, Ciphersuite(-1), options); err != errUnknownCiphersuite {
+		t.Fatalf("unknown ciphersuite")
+	}
+}
+
+func TestMinSigCross(t *testing.T) {
+	const nPublicKeys = 4
+	options := ValidationOptions{CheckSignatureSubgroup: true, ValidatePublicKey: true}
+	secretKeyBytes := make([][]byte, nPublicKeys)
+	messages := make([][]byte, nPublicKeys)
+	for i := 0; i < nPublicKeys; i++ {
+		secretKeyBytes[i] = randKilicSecretKey().ToBytes()
+		messages[i] = []byte(fmt.Sprintf("test %d", i))
+	}
+	message := []byte("test")
+	for _, ciphersuite := range []Ciphersuite{Basic, Aug, PoP} {
+		var expectedPublicKey, expectedSignature, expectedProof []byte
+		for _, suite := range newCiphersuites(t, MinSignatureSize, ciphersuite, options) {
+			name := ciphersuite.String() + " " + suite.Backend().Name()
+			if !bytes.HasPrefix(suite.DST(), []byte("BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_")) {
+				t.Fatalf("%s: tag", name)
+			}
+			secretKeys := make([]SecretKey, nPublicKeys)
+			publicKeys := make([]PublicKey, nPublicKeys)
+			signatures := make([]Signature, nPublicKeys)
+			sets := make([]SignatureSet, nPublicKeys)
+			for i := 0; i < nPublicKeys; i++ {
+				secretKey, err := suite.SecretKeyFromBytes(secretKeyBytes[i])
+				if err != nil {
+					t.Fatal(err)
+				}
+				secretKeys[i] = secretKey
+				publicKeys[i] = secretKey.PublicKey()
+				signatures[i] = suite.Sign(secretKey, messages[i])
+				sets[i] = SignatureSet{publicKeys[i], messages[i], signatures[i]}
+			}
+			publicKey := publicKeys[0]
+			signature := suite.Sign(secretKeys[0], message)
+			proof := suite.PopProve(secretKeys[0])
+			if len(publicKey.ToBytes()) != MinSigPublicKeySize || len(signature.ToBytes()) != MinSigSignatureSize {
+				t.Fatalf("%s: sizes", name)
+			}
+			if expectedPublicKey == nil {
+				expectedPublicKey, expectedSignature, expectedProof = publicKey.ToBytes(), signature.ToBytes(), proof.ToBytes()
+			} else {
+				if !bytes.Equal(expectedPublicKey, publicKey.ToBytes()) {
+					t.Fatalf("%s: public key", name)
+				}
+				if !bytes.Equal(expectedSignature, signature.ToBytes()) {
+					t.Fatalf("%s: signature", name)
+				}
+				if !bytes.Equal(expectedProof, proof.ToBytes()) {
+					t.Fatalf("%s: proof of possession", name)
+				}
+			}
+			if !suite.PopVerify(publicKey, proof) {
+				t.Fatalf("%s: proof of possession must be verified", name)
+			}
+			if !suite.Verify(signature, publicKey, message) {
+				t.Fatalf("%s: must be verified", name)
+			}
+			if suite.Verify(signature, publicKeys[1], message) {
+				t.Fatalf("%s: must not be verified", name)
+			}
+			if ciphersuite == PoP {
+				duplicates := make([]Signature, nPublicKeys)
+				for i := 0; i < nPublicKeys; i++ {
+					duplicates[i] = suite.Sign(secretKeys[i], message)
+				}
+				aggregated, err := suite.AggregateSignatures(duplicates)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !suite.FastAggregateVerify(aggregated, publicKeys, message) {
+					t.Fatalf("%s: must be verified", name)
+				}
+			}
+			aggregated, err := suite.AggregateSignatures(signatures)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !suite.AggregateVerify(aggregated, publicKeys, messages) {
+				t.Fatalf("%s: must be verified", name)
+			}
+			if suite.AggregateVerify(aggregated, publicKeys[1:], messages[1:]) {
+				t.Fatalf("%s: must not be verified", name)
+			}
+			if ok, err := suite.VerifyMultipleSignatures(sets); err != nil || !ok {
+				t.Fatalf("%s: signature sets must be verified", name)
+			}
+			sets[0].Message = message
+			if ok, err := suite.VerifyMultipleSignatures(sets); err != nil || ok {
+				t.Fatalf("%s: tampered signature sets must not be verified", name)
+			}
+			if _, err := suite.PublicKeyFromBytes(signature.ToBytes()); err != errPublicKeySize {
+				t.Fatalf("%s: public key size", name)
+			}
+			if _, err := suite.SignatureFromBytes(infinitePublicKey); err != errInfiniteSignature {
+				t.Fatalf("%s: infinite signature", name)
+			}
+			if _, err := suite.PublicKeyFromBytes(zeroSignature); err != errZeroPublicKey {
+				t.Fatalf("%s: zero public key", name)
+			}
+			if _, err := Convert(randKilicSecretKey().PublicKey(), suite.Backend()); err == nil {
+				t.Fatalf("%s: public key of other variant must not be converted", name)
+			}
+		}
+	}
+}
+
+// TestHashToSignatureGroupCross compares hashes to the signature group of herumi backends against blst.
+// The herumi binding only hashes to G2 with its own tag, so herumi backends hash with kilic for G1 and for
+// other tags. Their hashes are not independent of kilic, so they are checked against blst instead.
+func TestHashToSignatureGroupCross(t *testing.T) {
+	message := []byte("test")
+	for _, test := range []struct {
+		herumi, blst string
+		variant      Variant
+	}{
+		{libHerumi, libBLST, MinPublicKeySize},
+		{libHerumiMinSig, libBLSTMinSig, MinSignatureSize},
+	} {
+		for _, ciphersuite := range []Ciphersuite{Basic, Aug, PoP} {
+			name := ciphersuite.String() + " " + test.herumi
+			dst := ciphersuite.DST(test.variant)
+			hashes := [][]byte{}
+			for _, backendName := range []string{test.herumi, test.blst} {
+				backend, err := Lookup(backendName)
+				if err != nil {
+					t.Fatal(err)
+				}
+				initBackend(backend)
+				M, err := backend.(messageHasher).hashToSignatureGroup(message, dst)
+				if err != nil {
+					t.Fatal(err)
+				}
+				hashes = append(hashes, M.ToBytes())
+			}
+			if !bytes.Equal(hashes[0], hashes[1]) {
+				t.Fatalf("%s: hash does not match %s", name, test.blst)
+			}
+		}
+	}
+}
+
+// nonSubgroupG1Point returns uncompressed encoding of a point on G1 curve which is not in the subgroup.
+func nonSubgroupG1Point(t *testing.T) []byte {
+	p, _ := new(big.Int).SetString("1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab", 16)
+	exp := new(big.Int).Div(new(big.Int).Add(p, big.NewInt(1)), big.NewInt(4))
+	g := kilic.NewG1()
+	for x := int64(1); ; x++ {
+		_x := big.NewInt(x)
+		rhs := new(big.Int).Exp(_x, big.NewInt(3), p)
+		rhs.Add(rhs, big.NewInt(4)).Mod(rhs, p)
+		y := new(big.Int).Exp(rhs, exp, p)
+		if new(big.Int).Exp(y, big.NewInt(2), p).Cmp(rhs) != 0 {
+			continue
+		}
+		out := make([]byte, UncompressedPublicKeySize)
+		_x.FillBytes(out[:48])
+		y.FillBytes(out[48:])
+		point, err := g.FromBytes(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !g.InCorrectSubgroup(point) {
+			return out
+		}
+	}
+}
+
+func TestUncompressedCross(t *testing.T) {
+	nonSubgroup := nonSubgroupG1Point(t)
+	for _, variant := range []Variant{MinPublicKeySize, MinSignatureSize} {
+		suites := newCiphersuites(t, variant, PoP, defaultValidationOptions)
+		secretKeyBytes := randKilicSecretKey().ToBytes()
+		message := []byte("test")
+		var expectedPublicKey, expectedSignature []byte
+		for _, suite := range suites {
+			name := suite.Backend().Name()
+			secretKey, err := suite.SecretKeyFromBytes(secretKeyBytes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			publicKey := secretKey.PublicKey()
+			signature := suite.Sign(secretKey, message)
+			if expectedPublicKey == nil {
+				expectedPublicKey, expectedSignature = publicKey.ToUncompressed(), signature.ToUncompressed()
+				if len(expectedPublicKey) != variant.UncompressedPublicKeySize() || len(expectedSignature) != variant.UncompressedSignatureSize() {
+					t.Fatalf("%s: sizes", name)
+				}
+			} else {
+				if !bytes.Equal(expectedPublicKey, publicKey.ToUncompressed()) {
+					t.Fatalf("%s: public key", name)
+				}
+				if !bytes.Equal(expectedSignature, signature.ToUncompressed()) {
+					t.Fatalf("%s: signature", name)
+				}
+			}
+			// x coordinate is shared with compressed form
+			compressed := publicKey.ToBytes()
+			if !bytes.Equal(compressed[1:], expectedPublicKey[1:len(compressed)]) || compressed[0]&0x1f != expectedPublicKey[0] {
+				t.Fatalf("%s: x coordinate", name)
+			}
+			_publicKey, err := suite.PublicKeyFromUncompressed(expectedPublicKey)
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			_signature, err := suite.SignatureFromUncompressed(expectedSignature)
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			if !_publicKey.Equal(publicKey) || !_signature.Equal(signature) {
+				t.Fatalf("%s: round trip", name)
+			}
+			if !suite.Verify(_signature, _publicKey, message) {
+				t.Fatalf("%s: must be verified", name)
+			}
+		}
+
+		// every backend rejects invalid encodings with the same error
+		publicKeyCases := map[string][]byte{
+			"size":         expectedPublicKey[1:],
+			"zero":         make([]byte, len(expectedPublicKey)),
+			"infinity":     infiniteUncompressed(len(expectedPublicKey)),
+			"compressed":   append([]byte{expectedPublicKey[0] | 0x80}, expectedPublicKey[1:]...),
+			"not on curve": append(append([]byte{}, expectedPublicKey[:len(expectedPublicKey)-1]...), expectedPublicKey[len(expectedPublicKey)-1]^1),
+		}
+		signatureCases := map[string][]byte{
+			"size":         expectedSignature[1:],
+			"zero":         make([]byte, len(expectedSignature)),
+			"infinity":     infiniteUncompressed(len(expectedSignature)),
+			"compressed":   append([]byte{expectedSignature[0] | 0x80}, expectedSignature[1:]...),
+			"not on curve": append(append([]byte{}, expectedSignature[:len(expectedSignature)-1]...), expectedSignature[len(expectedSignature)-1]^1),
+		}
+		if variant == MinPublicKeySize {
+			publicKeyCases["not in subgroup"] = nonSubgroup
+		} else {
+			signatureCases["not in subgroup"] = nonSubgroup
+		}
+		for c, in := range publicKeyCases {
+			var expected error
+			for i, suite := range suites {
+				_, err := suite.PublicKeyFromUncompressed(in)
+				if err == nil {
+					t.Fatalf("%s: public key %s must be rejected", suite.Backend().Name(), c)
+				}
+				if i == 0 {
+					expected = err
+				} else if err != expected {
+					t.Fatalf("%s: public key %s error %v, expected %v", suite.Backend().Name(), c, err, expected)
+				}
+			}
+		}
+		for c, in := range signatureCases {
+			var expected error
+			for i, suite := range suites {
+				_, err := suite.SignatureFromUncompressed(in)
+				if err == nil {
+					t.Fatalf("%s: signature %s must be rejected", suite.Backend().Name(), c)
+				}
+				if i == 0 {
+					expected = err
+				} else if err != expected {
+					t.Fatalf("%s: signature %s error %v, expected %v", suite.Backend().Name(), c, err, expected)
+				}
+			}
+		}
+	}
+	// infinity is encoded with flag on every backend
+	infinite, err := kilicBackend{}.AggregateSignatures(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(infinite.ToUncompressed(), infiniteUncompressed(UncompressedSignatureSize)) {
+		t.Fatalf("kilic infinity")
+	}
+}
+
+func TestEIP2333(t *testing.T) {
+	// https://eips.ethereum.org/EIPS/eip-2333#test-cases
+	vectors := []struct {
+		seed   string
+		master string
+		index  uint32
+		child  string
+	}{
+		{
+			"c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+			"6083874454709270928345386274498605044986640685124978867557563392430687146096",
+			0,
+			"20397789859736650942317412262472558107875392172444076792671091975210932703118",
+		},
+		{
+			"3141592653589793238462643383279502884197169399375105820974944592",
+			"29757020647961307431480504535336562678282505419141012933316116377660817309383",
+			3141592653,
+			"25457201688850691947727629385191704516744796114925897962676248250929345014287",
+		},
+		{
+			"0099FF991111002299DD7744EE3355BBDD8844115566CC55663355668888CC00",
+			"27580842291869792442942448775674722299803720648445448686099262467207037398656",
+			4294967295,
+			"29358610794459428860402234341874281240803786294062035874021252734817515685787",
+		},
+		{
+			"d4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3",
+			"19022158461524446591288038168518313374041767046816487870552872741050760015818",
+			42,
+			"31372231650479070279774297061823572166496564838472787488249775572789064611981",
+		},
+	}
+	toBig := func(secretKey SecretKey) string {
+		return new(big.Int).SetBytes(secretKey.ToBytes()).String()
+	}
+	for _, suite := range newSuites(t, dst, defaultValidationOptions) {
+		name := suite.Backend().Name()
+		for i, v := range vectors {
+			seed, err := hex.DecodeString(v.seed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			master, err := suite.DeriveMasterSK(seed)
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			if toBig(master) != v.master {
+				t.Fatalf("%s: master secret key %d", name, i)
+			}
+			child, err := suite.DeriveChildSK(master, v.index)
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			if toBig(child) != v.child {
+				t.Fatalf("%s: child secret key %d", name, i)
+			}
+		}
+		if _, err := suite.DeriveMasterSK(make([]byte, 31)); err != errShortSeed {
+			t.Fatalf("%s: short seed", name)
+		}
+	}
+}
+
+func TestEIP2334(t *testing.T) {
+	for _, path := range []string{"", "m/", "m//0", "n/12381", "m/12380/3600/0", "m/12381/-1", "m/12381/+1", "m/12381/4294967296", "m/12381/0x1", "m", "m/12381", "m/12381/60/0/0", "m/12381/3601/0/0/0"} {
+		if _, err := ParsePath(path); err != errInvalidPath {
+			t.Fatalf("path %q must be rejected", path)
+		}
+	}
+	indices, err := ParsePath(SigningKeyPath(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(indices) != "[12381 3600 5 0 0]" {
+		t.Fatalf("signing key path %v", indices)
+	}
+	if WithdrawalKeyPath(5) != "m/12381/3600/5/0" {
+		t.Fatalf("withdrawal key path")
+	}
+	seed := make([]byte, 32)
+	if _, err := DeriveSKFromPath(seed, "m"); err != errInvalidPath {
+		t.Fatalf("master key path must be rejected")
+	}
+	expected, _ := DeriveMasterSK(seed)
+	for _, index := range indices {
+		expected, _ = DeriveChildSK(expected, index)
+	}
+	secretKey, err := DeriveSKFromPath(seed, SigningKeyPath(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !secretKey.Equal(expected) {
+		t.Fatalf("signing key")
+	}
+}
+
+func TestKeyGenCross(t *testing.T) {
+	ikm := make([]byte, 32)
+	if _, err := rand.Read(ikm); err != nil {
+		t.Fatal(err)
+	}
+	keyInfos := [][]byte{nil, []byte("key info")}
+	for _, variant := range []Variant{MinPublicKeySize, MinSignatureSize} {
+		for _, suite := range newCiphersuites(t, variant, PoP, defaultValidationOptions) {
+			name := suite.Backend().Name()
+			for _, keyInfo := range keyInfos {
+				secretKey, err := suite.KeyGen(ikm, keyInfo)
+				if err != nil {
+					t.Fatalf("%s: %v", name, err)
+				}
+				expected := blst.KeyGen(ikm, keyInfo).Serialize()
+				if !bytes.Equal(expected, secretKey.ToBytes()) {
+					t.Fatalf("%s: secret key must match blst keygen", name)
+				}
+			}
+			// EIP-2333 master key derivation is KeyGen without key info
+			secretKey, _ := suite.KeyGen(ikm, nil)
+			master, _ := suite.DeriveMasterSK(ikm)
+			if !secretKey.Equal(master) {
+				t.Fatalf("%s: master secret key", name)
+			}
+			if _, err := suite.KeyGen(make([]byte, 31), nil); err != errShortSeed {
+				t.Fatalf("%s: short ikm", name)
+			}
+		}
+	}
+}
+
+func TestThresholdCross(t *testing.T) {
+	threshold, size := 3, 5
+	message := []byte("test")
+	for _, variant := range []Variant{MinPublicKeySize, MinSignatureSize} {
+		suites := newCiphersuites(t, variant, PoP, ValidationOptions{})
+		secretKeyBytes := randKilicSecretKey().ToBytes()
+		for _, suite := range suites {
+			secretKey, err := suite.SecretKeyFromBytes(secretKeyBytes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			shares, err := suite.SplitSecretKey(secretKey, threshold, size)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(shares) != size {
+				t.Fatalf("%s number of shares", suite.Backend().Name())
+			}
+			// expected values are computed by kilic from the secret key, without interpolation
+			reference := suites[len(suites)-1]
+			referenceSecretKey, err := reference.SecretKeyFromBytes(secretKeyBytes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := reference.Sign(referenceSecretKey, message)
+			expectedPublicKey := referenceSecretKey.PublicKey()
+			// any threshold many shares recover the signature and the public key
+			for _, indices := range [][]uint32{{1, 2, 3}, {5, 3, 1}, {2, 4, 5}, {1, 2, 3, 4, 5}} {
+				partials := make(map[uint32]Signature)
+				publicKeys := make(map[uint32]PublicKey)
+				// shares interpolate to the secret key as scalars: f(0) = sum of f(i) * prod j / (j - i)
+				recovered := new(big.Int)
+				for _, i := range indices {
+					partials[i] = suite.Sign(shares[i], message)
+					publicKeys[i] = shares[i].PublicKey()
+					term := new(big.Int).SetBytes(shares[i].ToBytes())
+					for _, j := range indices {
+						if i != j {
+							denominator := new(big.Int).Mod(big.NewInt(int64(j)-int64(i)), groupOrder)
+							denominator.ModInverse(denominator, groupOrder)
+							term.Mul(term, big.NewInt(int64(j))).Mul(term, denominator).Mod(term, groupOrder)
+						}
+					}
+					recovered.Add(recovered, term).Mod(recovered, groupOrder)
+				}
+				if !bytes.Equal(recovered.FillBytes(make([]byte, SecretKeySize)), secretKeyBytes) {
+					t.Fatalf("%s shares do not interpolate to the secret key", suite.Backend().Name())
+				}
+				// partial signatures are recovered with other backends of the same variant
+				for _, other := range suites {
+					signature, err := other.RecoverSignature(partials)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if !bytes.Equal(expected.ToBytes(), signature.ToBytes()) {
+						t.Fatalf("%s %s recovered signature", suite.Backend().Name(), other.Backend().Name())
+					}
+					publicKey, err := other.RecoverPublicKey(publicKeys)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if !bytes.Equal(expectedPublicKey.ToBytes(), publicKey.ToBytes()) {
+						t.Fatalf("%s %s recovered public key", suite.Backend().Name(), other.Backend().Name())
+					}
+					if !other.Verify(signature, publicKey, message) {
+						t.Fatalf("%s %s must be verified", suite.Backend().Name(), other.Backend().Name())
+					}
+				}
+			}
+			partials := map[uint32]Signature{1: suite.Sign(shares[1], message), 2: suite.Sign(shares[2], message)}
+			signature, err := suite.RecoverSignature(partials)
+			if err == nil && bytes.Equal(expected.ToBytes(), signature.ToBytes()) {
+				t.Fatalf("%s must not be recovered with less than threshold shares", suite.Backend().Name())
+			}
+			if _, err := suite.RecoverSignature(map[uint32]Signature{0: expected}); err != errInvalidShareIndex {
+				t.Fatalf("%s zero index must be rejected", suite.Backend().Name())
+			}
+			if _, err := suite.RecoverSignature(map[uint32]Signature{}); err != errNoShares {
+				t.Fatalf("%s empty shares must be rejected", suite.Backend().Name())
+			}
+			if _, err := suite.SplitSecretKey(secretKey, size+1, size); err != errInvalidThreshold {
+				t.Fatalf("%s threshold must not exceed number of shares", suite.Backend().Name())
+			}
+		}
+	}
+	// backends that do not multiply points cannot recover
+	kilic, err := Lookup(libKilic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	suite := NewSuite(testBackend{kilic}, dst, ValidationOptions{})
+	if _, err := suite.RecoverPublicKey(map[uint32]PublicKey{1: suite.RandSecretKey().PublicKey()}); err != errUnsupported {
+		t.Fatalf("recovery error %v, expected %v", err, errUnsupported)
+	}
+}
+
+func TestEvaluateCommitmentsCross(t *testing.T) {
+	coefficients := []*big.Int{big.NewInt(3), big.NewInt(5), new(big.Int).Sub(GroupOrder(), big.NewInt(7))}
+	for _, variant := range []Variant{MinPublicKeySize, MinSignatureSize} {
+		for _, suite := range newCiphersuites(t, variant, PoP, ValidationOptions{}) {
+			commitments := make([]PublicKey, len(coefficients))
+			for i, coefficient := range coefficients {
+				secretKey, err := suite.secretKeyFromBig(coefficient)
+				if err != nil {
+					t.Fatal(err)
+				}
+				commitments[i] = secretKey.PublicKey()
+			}
+			for _, x := range []uint32{1, 2, 1 << 31} {
+				publicKey, err := suite.EvaluateCommitments(commitments, x)
+				if err != nil {
+					t.Fatal(err)
+				}
+				secretKey, err := suite.secretKeyFromBig(EvaluatePolynomial(coefficients, x))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !publicKey.Equal(secretKey.PublicKey()) {
+					t.Fatalf("%s commitments at %d", suite.Backend().Name(), x)
+				}
+			}
+			if _, err := suite.EvaluateCommitments(nil, 1); err != errInvalidThreshold {
+				t.Fatalf("%s empty commitments", suite.Backend().Name())
+			}
+			if _, err := NewSuite(testBackend{suite.Backend()}, dst, ValidationOptions{}).MulPublicKey(commitments[0], big.NewInt(2)); err != errUnsupported {
+				t.Fatalf("%s backends without point multiplication", suite.Backend().Name())
+			}
+		}
+	}
+}
+
+func TestCombineSignaturesCross(t *testing.T) {
+	threshold, size := 3, 5
+	message := []byte("test")
+	for _, variant := range []Variant{MinPublicKeySize, MinSignatureSize} {
+		suites := newCiphersuites(t, variant, PoP, ValidationOptions{})
+		for _, suite := range suites {
+			secretKey := suite.RandSecretKey()
+			shares, err := suite.SplitSecretKey(secretKey, threshold, size)
+			if err != nil {
+				t.Fatal(err)
+			}
+			partials := make(map[uint32]Signature)
+			publicKeyShares := make(map[uint32]PublicKey)
+			for index, share := range shares {
+				partials[index] = suite.Sign(share, message)
+				publicKeyShares[index] = share.PublicKey()
+			}
+			expected := suite.Sign(secretKey, message)
+			signature, invalid, err := suite.CombineSignatures(partials, publicKeyShares, message, threshold)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(invalid) != 0 || !bytes.Equal(expected.ToBytes(), signature.ToBytes()) {
+				t.Fatalf("%s combined signature", suite.Backend().Name())
+			}
+			// corrupt partials of signers 1 and 4 are reported and the rest recovers the signature
+			partials[1] = suite.Sign(shares[1], []byte("other"))
+			partials[4] = suite.Sign(suite.RandSecretKey(), message)
+			signature, invalid, err = suite.CombineSignatures(partials, publicKeyShares, message, threshold)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fmt.Sprint(invalid) != "[1 4]" {
+				t.Fatalf("%s invalid partials %v", suite.Backend().Name(), invalid)
+			}
+			if !bytes.Equal(expected.ToBytes(), signature.ToBytes()) {
+				t.Fatalf("%s combined signature with corrupt partials", suite.Backend().Name())
+			}
+			delete(publicKeyShares, 5)
+			_, invalid, err = suite.CombineSignatures(partials, publicKeyShares, message, threshold)
+			if err != errNotEnoughShares {
+				t.Fatalf("%s must not be combined with less than threshold valid partials", suite.Backend().Name())
+			}
+			if fmt.Sprint(invalid) != "[1 4 5]" {
+				t.Fatalf("%s invalid partials %v", suite.Backend().Name(), invalid)
+			}
+		}
+	}
+}
+
+func TestAggregatorCross(t *testing.T) {
+	size := 8
+	message := []byte("test")
+	for _, variant := range []Variant{MinPublicKeySize, MinSignatureSize} {
+		suites := newCiphersuites(t, variant, PoP, ValidationOptions{})
+		for _, suite := range suites {
+			publicKeys := make([]PublicKey, size)
+			signatures := make([]Signature, size)
+			for i := 0; i < size; i++ {
+				secretKey := suite.RandSecretKey()
+				publicKeys[i] = secretKey.PublicKey()
+				signatures[i] = suite.Sign(secretKey, message)
+			}
+			for _, other := range suites {
+				// values are added one at a time and aggregators of other backends are merged
+				publicKeyAggregator, signatureAggregator := suite.NewPublicKeyAggregator(), suite.NewSignatureAggregator()
+				otherPublicKeyAggregator, otherSignatureAggregator := other.NewPublicKeyAggregator(), other.NewSignatureAggregator()
+				for i := 0; i < size; i++ {
+					_publicKeyAggregator, _signatureAggregator := publicKeyAggregator, signatureAggregator
+					if i%2 == 1 {
+						_publicKeyAggregator, _signatureAggregator = otherPublicKeyAggregator, otherSignatureAggregator
+					}
+					if err := _publicKeyAggregator.Add(publicKeys[i]); err != nil {
+						t.Fatal(err)
+					}
+					if err := _signatureAggregator.Add(signatures[i]); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if err := publicKeyAggregator.Merge(otherPublicKeyAggregator); err != nil {
+					t.Fatal(err)
+				}
+				if err := signatureAggregator.Merge(otherSignatureAggregator); err != nil {
+					t.Fatal(err)
+				}
+				// remove the first signer
+				if err := publicKeyAggregator.Remove(publicKeys[0]); err != nil {
+					t.Fatal(err)
+				}
+				if err := signatureAggregator.Remove(signatures[0]); err != nil {
+					t.Fatal(err)
+				}
+				expectedPublicKey, err := suite.AggregatePublicKeys(publicKeys[1:])
+				if err != nil {
+					t.Fatal(err)
+				}
+				expectedSignature, err := suite.AggregateSignatures(signatures[1:])
+				if err != nil {
+					t.Fatal(err)
+				}
+				publicKey := publicKeyAggregator.Result()
+				if !bytes.Equal(expectedPublicKey.ToBytes(), publicKey.ToBytes()) {
+					t.Fatalf("%s %s aggregated public key", suite.Backend().Name(), other.Backend().Name())
+				}
+				signature := signatureAggregator.Result()
+				if !bytes.Equal(expectedSignature.ToBytes(), signature.ToBytes()) {
+					t.Fatalf("%s %s aggregated signature", suite.Backend().Name(), other.Backend().Name())
+				}
+				if !suite.FastAggregateVerify(signature, publicKeys[1:], message) {
+					t.Fatalf("%s %s must be verified", suite.Backend().Name(), other.Backend().Name())
+				}
+			}
+			// removing all values results infinity
+			aggregator := suite.NewSignatureAggregator()
+			for _, signature := range signatures[:2] {
+				if err := aggregator.Add(signature); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, signature := range signatures[:2] {
+				if err := aggregator.Remove(signature); err != nil {
+					t.Fatal(err)
+				}
+			}
+			_, infinite := variant.signatureEncodings()
+			if !bytes.Equal(infinite, aggregator.Result().ToBytes()) {
+				t.Fatalf("%s empty aggregate must be infinity", suite.Backend().Name())
+			}
+			if err := aggregator.Add(nil); err != errUnaggregatable {
+				t.Fatalf("%s nil signature must not be added", suite.Backend().Name())
+			}
+			if err := aggregator.Merge(nil); err != errUnaggregatable {
+				t.Fatalf("%s nil aggregator must not be merged", suite.Backend().Name())
+			}
+		}
+	}
+}
+
+func TestCommitteeAggregateCross(t *testing.T) {
+	size := 13
+	message := []byte("test")
+	for _, variant := range []Variant{MinPublicKeySize, MinSignatureSize} {
+		suites := newCiphersuites(t, variant, PoP, ValidationOptions{})
+		for _, suite := range suites {
+			name := suite.Backend().Name()
+			committee := make([]PublicKey, size)
+			signatures := make([]Signature, size)
+			for i := 0; i < size; i++ {
+				secretKey := suite.RandSecretKey()
+				committee[i] = secretKey.PublicKey()
+				signatures[i] = suite.Sign(secretKey, message)
+			}
+			aggregate := func(indices ...int) (Signature, Bitfield) {
+				bits := NewBitfield(size)
+				aggregator := suite.NewSignatureAggregator()
+				for _, i := range indices {
+					bits.Set(i)
+					if err := aggregator.Add(signatures[i]); err != nil {
+						t.Fatal(err)
+					}
+				}
+				return aggregator.Result(), bits
+			}
+			signature, bits := aggregate(0, 3, 8, 12)
+			if bits.Count() != 4 || !bits.Get(8) || bits.Get(9) || bits.Len() != size {
+				t.Fatalf("%s bitfield", name)
+			}
+			// aggregation_bits as an SSZ bitlist with the length delimiter at bit 13
+			if !bytes.Equal(bits, Bitfield{0x09, 0x31}) {
+				t.Fatalf("%s bitlist encoding %x", name, bits)
+			}
+			if ok, err := suite.VerifyCommitteeAggregate(signature, committee, Bitfield{0x09, 0x31}, message); err != nil || !ok {
+				t.Fatalf("%s bitlist must be verified", name)
+			}
+			if ok, err := suite.VerifyCommitteeAggregate(signature, committee, bits, message); err != nil || !ok {
+				t.Fatalf("%s must be verified", name)
+			}
+			bits.Set(5)
+			if ok, _ := suite.VerifyCommitteeAggregate(signature, committee, bits, message); ok {
+				t.Fatalf("%s must not be verified with another participation", name)
+			}
+			// empty participation is valid only with the signature at infinity
+			infinite, empty := aggregate()
+			if ok, err := suite.VerifyCommitteeAggregate(infinite, committee, empty, message); err != nil || !ok {
+				t.Fatalf("%s infinity signature must be verified with empty participation", name)
+			}
+			if ok, _ := suite.VerifyCommitteeAggregate(signatures[0], committee, empty, message); ok {
+				t.Fatalf("%s signature must not be verified with empty participation", name)
+			}
+			// aggregates of non overlapping participants are merged
+			signature1, bits1 := aggregate(0, 1, 2)
+			signature2, bits2 := aggregate(7, 11)
+			merged, mergedBits, err := suite.MergeCommitteeAggregates(signature1, bits1, signature2, bits2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected, expectedBits := aggregate(0, 1, 2, 7, 11)
+			if !bytes.Equal(expected.ToBytes(), merged.ToBytes()) || !bytes.Equal(expectedBits, mergedBits) {
+				t.Fatalf("%s merged aggregate", name)
+			}
+			if ok, err := suite.VerifyCommitteeAggregate(merged, committee, mergedBits, message); err != nil || !ok {
+				t.Fatalf("%s merged aggregate must be verified", name)
+			}
+			if _, _, err := suite.MergeCommitteeAggregates(signature1, bits1, merged, mergedBits); err != errBitfieldOverlap {
+				t.Fatalf("%s overlapping aggregates must not be merged", name)
+			}
+			// bitfields must match the committee size
+			if _, err := suite.VerifyCommitteeAggregate(signature1, committee[:8], bits1, message); err != errBitfieldSize {
+				t.Fatalf("%s bitfield size", name)
+			}
+			padded := append(Bitfield{}, bits1...)
+			padded[1] |= 0x80
+			if _, err := suite.VerifyCommitteeAggregate(signature1, committee, padded, message); err != errBitfieldSize {
+				t.Fatalf("%s bitfield padding", name)
+			}
+			// bitlist of a committee of 8 members has its delimiter in an extra byte
+			full := NewBitfield(8)
+			fullAggregator := suite.NewSignatureAggregator()
+			for i := 0; i < 8; i++ {
+				full.Set(i)
+				if err := fullAggregator.Add(signatures[i]); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if !bytes.Equal(full, Bitfield{0xff, 0x01}) {
+				t.Fatalf("%s bitlist encoding %x", name, full)
+			}
+			if ok, err := suite.VerifyCommitteeAggregate(fullAggregator.Result(), committee[:8], full, message); err != nil || !ok {
+				t.Fatalf("%s full participation must be verified", name)
+			}
+			if _, err := suite.VerifyCommitteeAggregate(fullAggregator.Result(), committee[:8], full[:1], message); err != errBitfieldSize {
+				t.Fatalf("%s bitfield without delimiter", name)
+			}
+			if _, _, err := suite.MergeCommitteeAggregates(signature1, bits1, signature2, bits2[:1]); err != errBitfieldSize {
+				t.Fatalf("%s bitfields of other committees must not be merged", name)
+			}
+		}
+	}
+}
+
+func TestEthCross(t *testing.T) {
+	message := []byte("test")
+	for _, variant := range []Variant{MinPublicKeySize, MinSignatureSize} {
+		suites := newCiphersuites(t, variant, PoP, ValidationOptions{})
+		for _, suite := range suites {
+			name := suite.Backend().Name()
+			publicKeys := make([]PublicKey, 3)
+			signatures := make([]Signature, 3)
+			for i := 0; i < len(publicKeys); i++ {
+				secretKey := suite.RandSecretKey()
+				publicKeys[i] = secretKey.PublicKey()
+				signatures[i] = suite.Sign(secretKey, message)
+			}
+			expected, err := suite.AggregatePublicKeys(publicKeys)
+			if err != nil {
+				t.Fatal(err)
+			}
+			aggregated, err := suite.EthAggregatePublicKeys(publicKeys)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(expected.ToBytes(), aggregated.ToBytes()) {
+				t.Fatalf("%s aggregated public key", name)
+			}
+			if _, err := suite.EthAggregatePublicKeys([]PublicKey{}); err != errEmptyPublicKeys {
+				t.Fatalf("%s empty public keys must be rejected", name)
+			}
+			infinite := suite.NewPublicKeyAggregator().Result()
+			if _, err := suite.EthAggregatePublicKeys([]PublicKey{publicKeys[0], infinite}); err != errInfinitePublicKey {
+				t.Fatalf("%s public key at infinity must be rejected", name)
+			}
+			negator := suite.NewPublicKeyAggregator()
+			if err := negator.Remove(publicKeys[0]); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := suite.EthAggregatePublicKeys([]PublicKey{publicKeys[0], negator.Result()}); err != errInfinitePublicKey {
+				t.Fatalf("%s aggregate at infinity must be rejected", name)
+			}
+			signature, err := suite.AggregateSignatures(signatures)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !suite.EthFastAggregateVerify(signature, publicKeys, message) {
+				t.Fatalf("%s must be verified", name)
+			}
+			if suite.EthFastAggregateVerify(signature, append(publicKeys, infinite), message) {
+				t.Fatalf("%s must not be verified with public key at infinity", name)
+			}
+			// sync committee aggregate without participants
+			infiniteSignature := suite.NewSignatureAggregator().Result()
+			if !suite.EthFastAggregateVerify(infiniteSignature, []PublicKey{}, message) {
+				t.Fatalf("%s signature at infinity must be verified without public keys", name)
+			}
+			if suite.EthFastAggregateVerify(signature, []PublicKey{}, message) {
+				t.Fatalf("%s signature must not be verified without public keys", name)
+			}
+			if suite.FastAggregateVerify(infiniteSignature, []PublicKey{}, message) {
+				t.Fatalf("%s fast aggregate verify must reject empty public keys", name)
+			}
+			// the signature at infinity is decoded from the wire only with AllowInfinity
+			if _, err := suite.SignatureFromBytes(infiniteSignature.ToBytes()); !errors.Is(err, ErrInfinity) {
+				t.Fatalf("%s signature at infinity must not be decoded by default", name)
+			}
+			allowInfinity, err := NewCiphersuite(suite.Backend(), PoP, ValidationOptions{AllowInfinity: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded, err := allowInfinity.SignatureFromBytes(infiniteSignature.ToBytes())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !allowInfinity.EthFastAggregateVerify(decoded, []PublicKey{}, message) {
+				t.Fatalf("%s decoded signature at infinity must be verified without public keys", name)
+			}
+		}
+	}
+	// public keys decoded without the subgroup check are checked in aggregation
+	nonSubgroup := nonSubgroupG1Point(t)
+	for _, suite := range newCiphersuites(t, MinPublicKeySize, PoP, ValidationOptions{SkipDecodeSubgroupCheck: true}) {
+		name := suite.Backend().Name()
+		publicKey, err := suite.PublicKeyFromUncompressed(nonSubgroup)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := suite.EthAggregatePublicKeys([]PublicKey{suite.RandSecretKey().PublicKey(), publicKey}); !errors.Is(err, ErrNotInSubgroup) {
+			t.Fatalf("%s public key out of subgroup error %v", name, err)
+		}
+	}
+}
+
+// compressedPoint returns compressed encoding of a point on G1 or G2 curve with the smallest x,
+// or of the smallest x that is not on curve. Points on curve with small x are not in the subgroup,
+// as the cofactors are large.
+func compressedPoint(t *testing.T, size int, onCurve bool) []byte {
+	for x := int64(1); x < 1000; x++ {
+		_x := big.NewInt(x)
+		var square bool
+		if size == fpByteSize {
+			square = isSquare(g1Curve(_x))
+		} else {
+			square = isSquare(g2Curve(fp2{_x, new(big.Int)}).norm())
+		}
+		if square != onCurve {
+			continue
+		}
+		out := make([]byte, size)
+		_x.FillBytes(out[size-fpByteSize:])
+		out[0] |= 0x80
+		return out
+	}
+	t.Fatalf("no point with on curve %v", onCurve)
+	return nil
+}
+
+func TestDecodingErrorsCross(t *testing.T) {
+	modulus, _ := hex.DecodeString("1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab")
+	// cases returns malformed encodings derived from valid encoding with the error they must be rejected with.
+	cases := func(valid []byte, compressed bool) map[string]error {
+		size := len(valid)
+		flipped := append([]byte{}, valid...)
+		flipped[0] ^= 0x80
+		infinity := make([]byte, size)
+		infinity[0] = 0x40
+		if compressed {
+			infinity[0] = 0xc0
+		}
+		dirtyInfinity := append([]byte{}, infinity...)
+		dirtyInfinity[size-1] = 1
+		nonCanonical := append([]byte{}, valid...)
+		copy(nonCanonical, modulus)
+		if compressed {
+			nonCanonical[0] |= 0x80
+		}
+		encodings := map[string]error{
+			string(valid[1:]):          ErrInvalidSize,
+			string(make([]byte, size)): ErrZero,
+			string(infinity):           ErrInfinity,
+			string(flipped):            ErrInvalidFlags,
+			string(dirtyInfinity):      ErrInvalidFlags,
+			string(nonCanonical):       ErrNonCanonical,
+		}
+		if compressed {
+			encodings[string(compressedPoint(t, size, false))] = ErrNotOnCurve
+			encodings[string(compressedPoint(t, size, true))] = ErrNotInSubgroup
+		} else {
+			notOnCurve := append([]byte{}, valid...)
+			notOnCurve[size-1] ^= 1
+			encodings[string(notOnCurve)] = ErrNotOnCurve
+			if size == UncompressedPublicKeySize {
+				encodings[string(nonSubgroupG1Point(t))] = ErrNotInSubgroup
+			}
+		}
+		return encodings
+	}
+	message := []byte("test")
+	for _, variant := range []Variant{MinPublicKeySize, MinSignatureSize} {
+		suites := newCiphersuites(t, variant, PoP, defaultValidationOptions)
+		secretKey, err := suites[0].SecretKeyFromBytes(randKilicSecretKey().ToBytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		publicKey, signature := secretKey.PublicKey(), suites[0].Sign(secretKey, message)
+		decoders := []struct {
+			kind   string
+			valid  []byte
+			decode func(suite *Suite, in []byte) error
+		}{
+			{"public key", publicKey.ToBytes(), func(suite *Suite, in []byte) error {
+				_, err := suite.PublicKeyFromBytes(in)
+				return err
+			}},
+			{"public key", publicKey.ToUncompressed(), func(suite *Suite, in []byte) error {
+				_, err := suite.PublicKeyFromUncompressed(in)
+				return err
+			}},
+			{"signature", signature.ToBytes(), func(suite *Suite, in []byte) error {
+				_, err := suite.SignatureFromBytes(in)
+				return err
+			}},
+			{"signature", signature.ToUncompressed(), func(suite *Suite, in []byte) error {
+				_, err := suite.SignatureFromUncompressed(in)
+				return err
+			}},
+		}
+		for _, decoder := range decoders {
+			compressed := decoder.valid[0]&0x80 != 0
+			for in, expected := range cases(decoder.valid, compressed) {
+				var first error
+				for _, suite := range suites {
+					name := suite.Backend().Name()
+					err := decoder.decode(suite, []byte(in))
+					if !errors.Is(err, expected) {
+						t.Fatalf("%s: %s error %v, expected %v", name, decoder.kind, err, expected)
+					}
+					var decodingError *DecodingError
+					if !errors.As(err, &decodingError) || decodingError.Kind != decoder.kind {
+						t.Fatalf("%s: %s decoding error %v", name, decoder.kind, err)
+					}
+					if first == nil {
+						first = err
+					} else if err != first {
+						t.Fatalf("%s: %s error %v, expected %v", name, decoder.kind, err, first)
+					}
+				}
+			}
+		}
+	}
+	for _, suite := range newSuites(t, dst, defaultValidationOptions) {
+		if _, err := suite.SecretKeyFromBytes(groupOrder.FillBytes(make([]byte, SecretKeySize))); !errors.Is(err, ErrNonCanonical) {
+			t.Fatalf("%s: secret key error %v", suite.Backend().Name(), err)
+		}
+		if _, err := suite.SecretKeyFromBytes(make([]byte, SecretKeySize)); !errors.Is(err, ErrZero) {
+			t.Fatalf("%s: secret key error %v", suite.Backend().Name(), err)
+		}
+	}
+}
+
+func TestDecodingErrorReasons(t *testing.T) {
+	onCurve, notOnCurve := compressedPoint(t, PublicKeySize, true), compressedPoint(t, PublicKeySize, false)
+	for _, test := range []struct {
+		encoded  []byte
+		reason   error
+		expected error
+	}{
+		// rejections that the backend does not confirm are not classified as out of subgroup
+		{onCurve, nil, errInvalidPublicKey},
+		{onCurve, ErrNotInSubgroup, errPublicKeySubgroup},
+		{notOnCurve, nil, errPublicKeyNotOnCurve},
+		// rules of the encoding are checked before the reason of the backend
+		{onCurve[1:], ErrNotInSubgroup, errPublicKeySize},
+		{make([]byte, PublicKeySize), ErrNotOnCurve, errZeroPublicKey},
+	} {
+		if err := publicKeyError(test.encoded, false, test.reason); err != test.expected {
+			t.Fatalf("public key error %v with reason %v, expected %v", err, test.reason, test.expected)
+		}
+	}
+	signature := compressedPoint(t, SignatureSize, true)
+	if err := signatureError(signature, true, nil); err != errInvalidSignature {
+		t.Fatalf("signature error %v, expected %v", err, errInvalidSignature)
+	}
+	if err := signatureError(signature, true, ErrNotInSubgroup); err != errSignatureSubgroup {
+		t.Fatalf("signature error %v, expected %v", err, errSignatureSubgroup)
+	}
+}
+
+func TestValidationOptionsCross(t *testing.T) {
+	relaxed := ValidationOptions{
+		CheckSignatureSubgroup:  true,
+		ValidatePublicKey:       true,
+		SkipDecodeSubgroupCheck: true,
+		AllowInfinity:           true,
+		AllowZero:               true,
+	}
+	nonSubgroup := map[int][]byte{
+		48: compressedPoint(t, 48, true),
+		96: compressedPoint(t, 96, true),
+	}
+	nonSubgroupG1 := nonSubgroupG1Point(t)
+	message := []byte("test")
+	for _, variant := range []Variant{MinPublicKeySize, MinSignatureSize} {
+		strict := newCiphersuites(t, variant, PoP, defaultValidationOptions)
+		suites := newCiphersuites(t, variant, PoP, relaxed)
+		type decoder struct {
+			kind   string
+			decode func(suite *Suite, in []byte) (interface{ ToUncompressed() []byte }, error)
+		}
+		publicKeyDecoder := decoder{"public key", func(suite *Suite, in []byte) (interface{ ToUncompressed() []byte }, error) {
+			if len(in) == variant.UncompressedPublicKeySize() {
+				return suite.PublicKeyFromUncompressed(in)
+			}
+			return suite.PublicKeyFromBytes(in)
+		}}
+		signatureDecoder := decoder{"signature", func(suite *Suite, in []byte) (interface{ ToUncompressed() []byte }, error) {
+			if len(in) == variant.UncompressedSignatureSize() {
+				return suite.SignatureFromUncompressed(in)
+			}
+			return suite.SignatureFromBytes(in)
+		}}
+		type encoding struct {
+			decoder decoder
+			in      []byte
+		}
+		encodings := []encoding{
+			{publicKeyDecoder, nonSubgroup[variant.PublicKeySize()]},
+			{signatureDecoder, nonSubgroup[variant.SignatureSize()]},
+		}
+		if variant == MinPublicKeySize {
+			encodings = append(encodings, encoding{publicKeyDecoder, nonSubgroupG1})
+		} else {
+			encodings = append(encodings, encoding{signatureDecoder, nonSubgroupG1})
+		}
+		// compressed points of both signs are decompressed the same way on every backend
+		for i := 0; i < 8; i++ {
+			secretKey, _ := strict[0].SecretKeyFromBytes(randKilicSecretKey().ToBytes())
+			encodings = append(encodings,
+				encoding{publicKeyDecoder, secretKey.PublicKey().ToBytes()},
+				encoding{signatureDecoder, strict[0].Sign(secretKey, message).ToBytes()},
+			)
+		}
+		for _, encoding := range encodings {
+			var expected []byte
+			for i, suite := range suites {
+				name := suite.Backend().Name()
+				value, err := encoding.decoder.decode(suite, encoding.in)
+				// kilic decompression always checks the subgroup
+				compressed := len(encoding.in) == variant.SignatureSize()
+				if encoding.decoder.kind == "public key" {
+					compressed = len(encoding.in) == variant.PublicKeySize()
+				}
+				if (name == libKilic || name == libKilicMinSig) && compressed && errors.Is(err, ErrNotInSubgroup) {
+					continue
+				}
+				if err != nil {
+					t.Fatalf("%s: %s must be decoded without subgroup check: %v", name, encoding.decoder.kind, err)
+				}
+				if i == 0 {
+					expected = value.ToUncompressed()
+				} else if !bytes.Equal(expected, value.ToUncompressed()) {
+					t.Fatalf("%s: %s decoded without subgroup check", name, encoding.decoder.kind)
+				}
+				if _, err := encoding.decoder.decode(strict[i], encoding.in); err != nil && !errors.Is(err, ErrNotInSubgroup) {
+					t.Fatalf("%s: %s error %v", name, encoding.decoder.kind, err)
+				}
+			}
+		}
+		for i, suite := range suites {
+			name := suite.Backend().Name()
+			_, infinite := variant.publicKeyEncodings()
+			for _, in := range [][]byte{make([]byte, variant.PublicKeySize()), infinite, make([]byte, variant.UncompressedPublicKeySize())} {
+				publicKey, err := publicKeyDecoder.decode(suite, in)
+				if err != nil {
+					t.Fatalf("%s: public key at infinity must be decoded: %v", name, err)
+				}
+				if !bytes.Equal(infinite, publicKey.(PublicKey).ToBytes()) {
+					t.Fatalf("%s: public key at infinity", name)
+				}
+				if _, err := publicKeyDecoder.decode(strict[i], in); err == nil {
+					t.Fatalf("%s: public key at infinity must be rejected", name)
+				}
+			}
+			_, infinite = variant.signatureEncodings()
+			for _, in := range [][]byte{make([]byte, variant.SignatureSize()), infinite, make([]byte, variant.UncompressedSignatureSize())} {
+				signature, err := signatureDecoder.decode(suite, in)
+				if err != nil {
+					t.Fatalf("%s: signature at infinity must be decoded: %v", name, err)
+				}
+				if !bytes.Equal(infinite, signature.(Signature).ToBytes()) {
+					t.Fatalf("%s: signature at infinity", name)
+				}
+			}
+			onlyInfinity, err := NewCiphersuite(suite.Backend(), PoP, ValidationOptions{AllowInfinity: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := onlyInfinity.PublicKeyFromBytes(make([]byte, variant.PublicKeySize())); !errors.Is(err, ErrZero) {
+				t.Fatalf("%s: zero public key error %v", name, err)
+			}
+			// points outside of the subgroup are rejected in verification
+			secretKey, _ := suite.SecretKeyFromBytes(randKilicSecretKey().ToBytes())
+			signature := suite.Sign(secretKey, message)
+			if variant == MinPublicKeySize {
+				publicKey, _ := suite.PublicKeyFromUncompressed(nonSubgroupG1)
+				if suite.Verify(signature, publicKey, message) || suite.FastAggregateVerify(signature, []PublicKey{publicKey}, message) {
+					t.Fatalf("%s: public key out of subgroup must not be verified", name)
+				}
+			} else {
+				nonSubgroupSignature, _ := suite.SignatureFromUncompressed(nonSubgroupG1)
+				if suite.Verify(nonSubgroupSignature, secretKey.PublicKey(), message) {
+					t.Fatalf("%s: signature out of subgroup must not be verified", name)
+				}
+			}
+		}
+	}
+}
+
+// Duplicate messages are only rejected by Basic ciphersuite, so every backend accepts them in
+// aggregate verification of other ciphersuites and of signature methods.
+func TestAggregateVerifyDuplicateMessagesCross(t *testing.T) {
+	const nPublicKeys = 3
+	message := []byte("test")
+	for _, variant := range []Variant{MinPublicKeySize, MinSignatureSize} {
+		for _, ciphersuite := range []Ciphersuite{Basic, PoP} {
+			for _, suite := range newCiphersuites(t, variant, ciphersuite, defaultValidationOptions) {
+				name := ciphersuite.String() + " " + suite.Backend().Name()
+				publicKeys := make([]PublicKey, nPublicKeys)
+				signatures := make([]Signature, nPublicKeys)
+				messages := make([][]byte, nPublicKeys)
+				for i := 0; i < nPublicKeys; i++ {
+					secretKey := suite.RandSecretKey()
+					publicKeys[i] = secretKey.PublicKey()
+					signatures[i] = suite.Sign(secretKey, message)
+					messages[i] = message
+				}
+				aggregated, err := suite.AggregateSignatures(signatures)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if suite.AggregateVerify(aggregated, publicKeys, messages) != (ciphersuite == PoP) {
+					t.Fatalf("%s: duplicate messages must be rejected only in Basic", name)
+				}
+				if ciphersuite == PoP && !aggregated.AggregateVerify(publicKeys, messages) {
+					t.Fatalf("%s: duplicate messages must be accepted by signature method", name)
+				}
+			}
+		}
+	}
+}
+
+func TestSuiteConcurrent(t *testing.T) {
+	suites := newSuites(t, dst, defaultValidationOptions)
+	errs := make(chan error, len(suites))
+	for _, suite := range suites {
+		go func(suite *Suite) {
+			for i := 0; i < 10; i++ {
+				message := []byte(fmt.Sprintf("test %d", i))
+				secretKey := suite.RandSecretKey()
+				signature := suite.Sign(secretKey, message)
+				if !suite.Verify(signature, secretKey.PublicKey(), message) {
+					errs <- fmt.Errorf("%s must be verified", suite.Backend().Name())
+					return
+				}
+			}
+			errs <- nil
+		}(suite)
+	}
+	for range suites {
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// TestUseBackendConcurrent switches backends while package level functions run, run it with -race.
+func TestUseBackendConcurrent(t *testing.T) {
+	previousSuite := currentSuite()
+	defer defaultSuite.Store(previousSuite)
+	names := []string{libHerumi, libBLST, libKilic}
+	errs := make(chan error, len(names)+1)
+	go func() {
+		for i := 0; i < 30; i++ {
+			if err := UseBackend(names[i%len(names)]); err != nil {
+				errs <- err
+				return
+			}
+		}
+		errs <- nil
+	}()
+	for range names {
+		go func() {
+			for i := 0; i < 10; i++ {
+				message := []byte(fmt.Sprintf("test %d", i))
+				secretKey := RandSecretKey()
+				publicKey := secretKey.PublicKey()
+				signature := secretKey.Sign(message)
+				if !EthFastAggregateVerify(signature, []PublicKey{publicKey}, message) {
+					errs <- fmt.Errorf("must be verified")
+					return
+				}
+				if !PopVerify(publicKey, PopProve(secretKey)) {
+					errs <- fmt.Errorf("proof of possession must be verified")
+					return
+				}
+			}
+			errs <- nil
+		}()
+	}
+	for i := 0; i < len(names)+1; i++ {
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	suites := newSuites(t, dst, defaultValidationOptions)
+	message := []byte("test")
+	for _, from := range suites {
+		secretKey := from.RandSecretKey()
+		publicKey := secretKey.PublicKey()
+		signature := from.Sign(secretKey, message)
+		for _, to := range suites {
+			name := from.Backend().Name() + " to " + to.Backend().Name()
+			for _, value := range []interface{}{secretKey, publicKey, signature} {
+				converted, err := Convert(value, to.Backend())
+				if err != nil {
+					t.Fatalf("%s: %v", name, err)
+				}
+				switch v := value.(type) {
+				case SecretKey:
+					if !bytes.Equal(v.ToBytes(), converted.(SecretKey).ToBytes()) || !converted.(SecretKey).Equal(v) {
+						t.Fatalf("%s: secret key", name)
+					}
+				case PublicKey:
+					if !bytes.Equal(v.ToBytes(), converted.(PublicKey).ToBytes()) || !converted.(PublicKey).Equal(v) {
+						t.Fatalf("%s: public key", name)
+					}
+				case Signature:
+					if !bytes.Equal(v.ToBytes(), converted.(Signature).ToBytes()) || !converted.(Signature).Equal(v) {
+						t.Fatalf("%s: signature", name)
+					}
+				}
+			}
+			if !to.Verify(signature, publicKey, message) {
+				t.Fatalf("%s: mixed values must be verified", name)
+			}
+			if !to.Sign(secretKey, message).Equal(signature) {
+				t.Fatalf("%s: mixed secret key must sign", name)
+			}
+		}
+	}
+	if _, err := Convert([]byte{}, suites[0].Backend()); err != errUnconvertible {
+		t.Fatalf("unsupported value must not be converted")
+	}
+}
+
+func TestMixedBackends(t *testing.T) {
+	const nPublicKeys = 9
+	suites := newSuites(t, dst, defaultValidationOptions)
+	message := []byte("test")
+	messages := make([][]byte, nPublicKeys)
+	publicKeys := make([]PublicKey, nPublicKeys)
+	signatures := make([]Signature, nPublicKeys)
+	aggregateSignatures := make([]Signature, nPublicKeys)
+	for i := 0; i < nPublicKeys; i++ {
+		suite := suites[i%len(suites)]
+		secretKey := suite.RandSecretKey()
+		messages[i] = []byte(fmt.Sprintf("test %d", i))
+		publicKeys[i] = secretKey.PublicKey()
+		signatures[i] = suite.Sign(secretKey, message)
+		aggregateSignatures[i] = suite.Sign(secretKey, messages[i])
+	}
+	for _, suite := range suites {
+		name := suite.Backend().Name()
+		signature, err := suite.AggregateSignatures(signatures)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !suite.FastAggregateVerify(signature, publicKeys, message) {
+			t.Fatalf("%s: must be verified", name)
+		}
+		signature, err = suite.AggregateSignatures(aggregateSignatures)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !suite.AggregateVerify(signature, publicKeys, messages) {
+			t.Fatalf("%s: must be verified", name)
+		}
+		publicKey, err := suite.AggregatePublicKeys(publicKeys)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		for _, other := range suites {
+			_publicKey, _ := other.AggregatePublicKeys(publicKeys)
+			if !publicKey.Equal(_publicKey) {
+				t.Fatalf("%s: aggregated public keys", name)
+			}
+		}
+	}
+	// infinity signature is rejected by blst and herumi decoders
+	infinite, err := suites[2].AggregateSignatures(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, suite := range suites[:2] {
+		if signatures[0].Equal(infinite) || infinite.Equal(signatures[0]) {
+			t.Fatalf("%s: must not be equal", suite.Backend().Name())
+		}
+		if suite.Verify(infinite, publicKeys[0], message) {
+			t.Fatalf("%s: must not be verified", suite.Backend().Name())
+		}
+		if _, err := suite.AggregateSignatures([]Signature{signatures[0], infinite}); err == nil {
+			t.Fatalf("%s: unconvertible signature must not be aggregated", suite.Backend().Name())
+		}
+	}
+}
+
+func TestVerifyMultipleSignatures(t *testing.T) {
+	const nSets = 8
+	customDST := []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_NUL_")
+	options := ValidationOptions{CheckSignatureSubgroup: true, ValidatePublicKey: true}
+	for _, _dst := range [][]byte{dst, customDST} {
+		suites := newSuites(t, _dst, options)
+		// 32 bytes messages go through native herumi multi verification
+		for _, messageSize := range []int{32, 13} {
+			sets := make([]SignatureSet, nSets)
+			for i := 0; i < nSets; i++ {
+				suite := suites[i%len(suites)]
+				secretKey := suite.RandSecretKey()
+				message := make([]byte, messageSize)
+				_, _ = rand.Read(message)
+				sets[i] = SignatureSet{secretKey.PublicKey(), message, suite.Sign(secretKey, message)}
+			}
+			wrongMessage := append([]SignatureSet{}, sets...)
+			wrongMessage[3].Message = sets[4].Message
+			swapped := append([]SignatureSet{}, sets...)
+			swapped[1].Signature, swapped[2].Signature = sets[2].Signature, sets[1].Signature
+			for _, suite := range suites {
+				name := suite.Backend().Name()
+				ok, err := suite.VerifyMultipleSignatures(sets)
+				if err != nil {
+					t.Fatalf("%s: %v", name, err)
+				}
+				if !ok {
+					t.Fatalf("%s: must be verified", name)
+				}
+				ok, err = suite.VerifyMultipleSignatures(sets[:1])
+				if err != nil || !ok {
+					t.Fatalf("%s: single set must be verified", name)
+				}
+				for _, tampered := range [][]SignatureSet{wrongMessage, swapped} {
+					ok, err = suite.VerifyMultipleSignatures(tampered)
+					if err != nil {
+						t.Fatalf("%s: %v", name, err)
+					}
+					if ok {
+						t.Fatalf("%s: tampered batch must not be verified", name)
+					}
+				}
+				if _, err := suite.VerifyMultipleSignatures(nil); err != errEmptySignatureSets {
+					t.Fatalf("%s: empty batch", name)
+				}
+				if _, err := suite.VerifyMultipleSignatures([]SignatureSet{{Message: sets[0].Message}}); err != errInvalidSignatureSet {
+					t.Fatalf("%s: invalid set", name)
+				}
+			}
+		}
+	}
 }
 
 func TestSecretKeySerialization(t *testing.T) {
@@ -107,7 +1802,7 @@ func TestSecretKeySerialization(t *testing.T) {
 		t.Fatal(err)
 	}
 	_, err = SecretKeyFromBytes(secretKeyBytes)
-	if err != errInvalidSecretKey {
+	if err != errSecretKeyNonCanonical {
 		t.Fatalf("large secret key")
 	}
 	secretKeyBytes, err = hex.DecodeString("73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000000")
@@ -364,3 +2059,21 @@ func BenchmarkAggregateVerify(t *testing.B) {
 		})
 	}
 }
+
+func BenchmarkVerifyMultipleSignatures(t *testing.B) {
+	for _, n := range []int{10, 100} {
+		t.Run(fmt.Sprintf("%d", n), func(t *testing.B) {
+			sets := make([]SignatureSet, n)
+			for i := 0; i < n; i++ {
+				message := make([]byte, 32)
+				rand.Read(message)
+				secretKey := RandSecretKey()
+				sets[i] = SignatureSet{secretKey.PublicKey(), message, secretKey.Sign(message)}
+			}
+			t.ResetTimer()
+			for i := 0; i < t.N; i++ {
+				VerifyMultipleSignatures(sets)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package cross_bls
+
+import "errors"
+
+// Ciphersuite is one of BLS signature schemes defined in draft-irtf-cfrg-bls-signature.
+type Ciphersuite int
+
+const (
+	// PoP is the proof of possession scheme. Public keys are expected to come with a proof of possession
+	// and fast aggregate verification is allowed.
+	PoP Ciphersuite = iota
+	// Basic is the basic scheme. Aggregate verification requires all messages to be distinct.
+	Basic
+	// Aug is the message augmentation scheme. Compressed public key is prepended to every signed message.
+	Aug
+)
+
+var errUnknownCiphersuite = errors.New("unknown ciphersuite")
+
+var ciphersuiteTags = map[Ciphersuite]string{
+	PoP:   "POP_",
+	Basic: "NUL_",
+	Aug:   "AUG_",
+}
+
+// DST returns the domain separation tag of the ciphersuite for the variant.
+func (ciphersuite Ciphersuite) DST(variant Variant) []byte {
+	return []byte("BLS_SIG_BLS12381" + variant.hashGroup() + "_XMD:SHA-256_SSWU_RO_" + ciphersuiteTags[ciphersuite])
+}
+
+func (ciphersuite Ciphersuite) String() string {
+	switch ciphersuite {
+	case PoP:
+		return "PoP"
+	case Basic:
+		return "Basic"
+	case Aug:
+		return "Aug"
+	}
+	return "unknown"
+}
+
+// NewCiphersuite returns a suite which runs operations of the ciphersuite with given backend and options.
+func NewCiphersuite(backend Backend, ciphersuite Ciphersuite, options ValidationOptions) (*Suite, error) {
+	if _, ok := ciphersuiteTags[ciphersuite]; !ok {
+		return nil, errUnknownCiphersuite
+	}
+	suite := NewSuite(backend, ciphersuite.DST(backend.Variant()), options)
+	suite.ciphersuite = ciphersuite
+	return suite, nil
+}
+
+// augment prepends compressed public key to the message.
+func augment(publicKey PublicKey, message []byte) []byte {
+	return append(publicKey.ToBytes(), message...)
+}
+
+// distinct returns true if no two messages are equal.
+func distinct(messages [][]byte) bool {
+	set := make(map[string]struct{}, len(messages))
+	for _, message := range messages {
+		if _, ok := set[string(message)]; ok {
+			return false
+		}
+		set[string(message)] = struct{}{}
+	}
+	return true
+}
@@ -0,0 +1,114 @@
+package cross_bls
+
+// Bitfield marks participating members of an ordered committee as an SSZ bitlist, which is the encoding
+// of aggregation_bits of attestations. Participation of the i-th member is the i-th bit of byte i / 8
+// counting from the least significant bit, and the bit following the last member is set as the length delimiter.
+// Bitfield of a committee of size n is n / 8 + 1 bytes long and has no bits set after the delimiter.
+type Bitfield []byte
+
+// NewBitfield returns an empty bitfield for a committee of given size.
+func NewBitfield(size int) Bitfield {
+	bits := make(Bitfield, size/8+1)
+	bits[size/8] = 1 << uint(size%8)
+	return bits
+}
+
+// Set marks the member at index as participating. Index must be less than the committee size.
+func (bits Bitfield) Set(index int) {
+	bits[index/8] |= 1 << uint(index%8)
+}
+
+// Get returns true if the member at index is participating.
+func (bits Bitfield) Get(index int) bool {
+	return bits[index/8]&(1<<uint(index%8)) != 0
+}
+
+// Len returns the committee size, which is the index of the length delimiter,
+// or -1 if bitfield has no delimiter.
+func (bits Bitfield) Len() int {
+	if len(bits) == 0 || bits[len(bits)-1] == 0 {
+		return -1
+	}
+	size := (len(bits) - 1) * 8
+	for last := bits[len(bits)-1]; last > 1; last >>= 1 {
+		size++
+	}
+	return size
+}
+
+// Count returns the number of participating members.
+func (bits Bitfield) Count() int {
+	count := 0
+	for i := 0; i < bits.Len(); i++ {
+		if bits.Get(i) {
+			count++
+		}
+	}
+	return count
+}
+
+// Overlaps returns true if a member is marked in both bitfields.
+func (bits Bitfield) Overlaps(other Bitfield) bool {
+	for i := 0; i < bits.Len() && i < other.Len(); i++ {
+		if bits.Get(i) && other.Get(i) {
+			return true
+		}
+	}
+	return false
+}
+
+// check returns an error if bitfield is not of a committee of given size.
+func (bits Bitfield) check(size int) error {
+	if bits.Len() != size {
+		return errBitfieldSize
+	}
+	return nil
+}
+
+// VerifyCommitteeAggregate verifies aggregate signature of committee members marked in bitfield on message.
+// Aggregates are verified with EthFastAggregateVerify, so an aggregate without participants is only
+// valid if the signature is the point at infinity.
+func VerifyCommitteeAggregate(signature Signature, committee []PublicKey, bits Bitfield, message []byte) (bool, error) {
+	return currentSuite().VerifyCommitteeAggregate(signature, committee, bits, message)
+}
+
+// MergeCommitteeAggregates merges aggregate signatures of the same committee and message
+// which bitfields do not overlap, and returns the merged signature with its bitfield.
+func MergeCommitteeAggregates(signature Signature, bits Bitfield, otherSignature Signature, otherBits Bitfield) (Signature, Bitfield, error) {
+	return currentSuite().MergeCommitteeAggregates(signature, bits, otherSignature, otherBits)
+}
+
+func (suite *Suite) VerifyCommitteeAggregate(signature Signature, committee []PublicKey, bits Bitfield, message []byte) (bool, error) {
+	if err := bits.check(len(committee)); err != nil {
+		return false, err
+	}
+	participants := []PublicKey{}
+	for i := 0; i < len(committee); i++ {
+		if bits.Get(i) {
+			participants = append(participants, committee[i])
+		}
+	}
+	return suite.EthFastAggregateVerify(signature, participants, message), nil
+}
+
+func (suite *Suite) MergeCommitteeAggregates(signature Signature, bits Bitfield, otherSignature Signature, otherBits Bitfield) (Signature, Bitfield, error) {
+	if bits.Len() < 0 || bits.Len() != otherBits.Len() {
+		return nil, nil, errBitfieldSize
+	}
+	if bits.Overlaps(otherBits) {
+		return nil, nil, errBitfieldOverlap
+	}
+	if signature == nil || otherSignature == nil {
+		return nil, nil, errInvalidSignature
+	}
+	merged, err := suite.AggregateSignatures([]Signature{signature, otherSignature})
+	if err != nil {
+		return nil, nil, err
+	}
+	// both bitfields have the same length delimiter, which is kept in the merged bitfield
+	mergedBits := make(Bitfield, len(bits))
+	for i := 0; i < len(bits); i++ {
+		mergedBits[i] = bits[i] | otherBits[i]
+	}
+	return merged, mergedBits, nil
+}
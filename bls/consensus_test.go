@@ -0,0 +1,411 @@
+package cross_bls
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Consensus spec bls vectors are laid out as <handler>/<suite>/<case>/data.yaml.
+// A small subset is vendored, the full set is run with
+// -consensus-vectors <consensus-spec-tests>/tests/general/phase0/bls (and altair/bls).
+var consensusVectors = flag.String("consensus-vectors", "./test_vectors/consensus", "directory of consensus spec bls test vectors")
+
+var hashToG2DST = []byte("QUUX-V01-CS02-with-BLS12381G2_XMD:SHA-256_SSWU_RO_")
+
+type consensusCase struct {
+	Input  yaml.Node `yaml:"input"`
+	Output yaml.Node `yaml:"output"`
+}
+
+type consensusHandler func(suite *Suite, input *yaml.Node, output *yaml.Node) error
+
+var consensusHandlers = map[string]consensusHandler{
+	"sign":                      consensusSign,
+	"verify":                    consensusVerify,
+	"aggregate":                 consensusAggregate,
+	"fast_aggregate_verify":     consensusFastAggregateVerify,
+	"aggregate_verify":          consensusAggregateVerify,
+	"eth_aggregate_pubkeys":     consensusEthAggregatePubkeys,
+	"eth_fast_aggregate_verify": consensusEthFastAggregateVerify,
+	"batch_verify":              consensusBatchVerify,
+	"deserialization_G1":        consensusDeserializationG1,
+	"deserialization_G2":        consensusDeserializationG2,
+	"hash_to_G2":                consensusHashToG2,
+}
+
+func TestConsensusVectors(t *testing.T) {
+	// vectors treat the point at infinity as a valid encoding, which verification still rejects as a public key
+	suites := newSuites(t, dst, ValidationOptions{CheckSignatureSubgroup: true, ValidatePublicKey: true, AllowInfinity: true})
+	cases := 0
+	err := filepath.Walk(*consensusVectors, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || info.Name() != "data.yaml" {
+			return err
+		}
+		rel, err := filepath.Rel(*consensusVectors, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		handlerName := strings.Split(filepath.ToSlash(rel), "/")[0]
+		handler, ok := consensusHandlers[handlerName]
+		if !ok {
+			return fmt.Errorf("unknown handler %s", handlerName)
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		c := new(consensusCase)
+		if err := yaml.Unmarshal(data, c); err != nil {
+			return err
+		}
+		cases++
+		for _, suite := range suites {
+			t.Run(suite.Backend().Name()+"/"+filepath.ToSlash(rel), func(t *testing.T) {
+				if err := handler(suite, &c.Input, &c.Output); err != nil {
+					t.Fatal(err)
+				}
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cases == 0 {
+		t.Fatal("no test vectors found")
+	}
+}
+
+func consensusHex(s string) ([]byte, error) {
+	return hex.DecodeString(strings.TrimPrefix(s, "0x"))
+}
+
+func consensusHexList(in []string) ([][]byte, error) {
+	out := make([][]byte, len(in))
+	for i := 0; i < len(in); i++ {
+		b, err := consensusHex(in[i])
+		if err != nil {
+			return nil, err
+		}
+		out[i] = b
+	}
+	return out, nil
+}
+
+func consensusPublicKeys(suite *Suite, in []string) ([]PublicKey, error) {
+	encoded, err := consensusHexList(in)
+	if err != nil {
+		return nil, err
+	}
+	publicKeys := make([]PublicKey, len(encoded))
+	for i := 0; i < len(encoded); i++ {
+		if publicKeys[i], err = suite.PublicKeyFromBytes(encoded[i]); err != nil {
+			return nil, err
+		}
+	}
+	return publicKeys, nil
+}
+
+// expectBool compares a boolean result with the expected output.
+func expectBool(output *yaml.Node, result bool) error {
+	var expected bool
+	if err := output.Decode(&expected); err != nil {
+		return err
+	}
+	if expected != result {
+		return fmt.Errorf("expected %v, got %v", expected, result)
+	}
+	return nil
+}
+
+// expectBytes compares an encoded result with the expected output. Nil result is expected to be null.
+func expectBytes(output *yaml.Node, result []byte) error {
+	if output.Tag == "!!null" {
+		if result != nil {
+			return fmt.Errorf("expected null, got %x", result)
+		}
+		return nil
+	}
+	var _expected string
+	if err := output.Decode(&_expected); err != nil {
+		return err
+	}
+	expected, err := consensusHex(_expected)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(expected, result) {
+		return fmt.Errorf("expected %x, got %x", expected, result)
+	}
+	return nil
+}
+
+func consensusSign(suite *Suite, input *yaml.Node, output *yaml.Node) error {
+	var in struct{ Privkey, Message string }
+	if err := input.Decode(&in); err != nil {
+		return err
+	}
+	_secretKey, err := consensusHex(in.Privkey)
+	if err != nil {
+		return err
+	}
+	message, err := consensusHex(in.Message)
+	if err != nil {
+		return err
+	}
+	secretKey, err := suite.SecretKeyFromBytes(_secretKey)
+	if err != nil {
+		return expectBytes(output, nil)
+	}
+	return expectBytes(output, suite.Sign(secretKey, message).ToBytes())
+}
+
+func consensusVerify(suite *Suite, input *yaml.Node, output *yaml.Node) error {
+	var in struct{ Pubkey, Message, Signature string }
+	if err := input.Decode(&in); err != nil {
+		return err
+	}
+	message, err := consensusHex(in.Message)
+	if err != nil {
+		return err
+	}
+	publicKeys, err := consensusPublicKeys(suite, []string{in.Pubkey})
+	if err != nil {
+		return expectBool(output, false)
+	}
+	signature, err := consensusSignature(suite, in.Signature)
+	if err != nil {
+		return expectBool(output, false)
+	}
+	return expectBool(output, suite.Verify(signature, publicKeys[0], message))
+}
+
+func consensusSignature(suite *Suite, in string) (Signature, error) {
+	signature, err := consensusHex(in)
+	if err != nil {
+		return nil, err
+	}
+	return suite.SignatureFromBytes(signature)
+}
+
+func consensusAggregate(suite *Suite, input *yaml.Node, output *yaml.Node) error {
+	var in []string
+	if err := input.Decode(&in); err != nil {
+		return err
+	}
+	if len(in) == 0 {
+		return expectBytes(output, nil)
+	}
+	aggregator := suite.NewSignatureAggregator()
+	for _, _signature := range in {
+		signature, err := consensusSignature(suite, _signature)
+		if err != nil {
+			return expectBytes(output, nil)
+		}
+		if err := aggregator.Add(signature); err != nil {
+			return err
+		}
+	}
+	return expectBytes(output, aggregator.Result().ToBytes())
+}
+
+func consensusFastAggregateVerify(suite *Suite, input *yaml.Node, output *yaml.Node) error {
+	var in struct {
+		Pubkeys   []string
+		Message   string
+		Signature string
+	}
+	if err := input.Decode(&in); err != nil {
+		return err
+	}
+	message, err := consensusHex(in.Message)
+	if err != nil {
+		return err
+	}
+	publicKeys, err := consensusPublicKeys(suite, in.Pubkeys)
+	if err != nil {
+		return expectBool(output, false)
+	}
+	signature, err := consensusSignature(suite, in.Signature)
+	if err != nil {
+		return expectBool(output, false)
+	}
+	return expectBool(output, suite.FastAggregateVerify(signature, publicKeys, message))
+}
+
+func consensusAggregateVerify(suite *Suite, input *yaml.Node, output *yaml.Node) error {
+	var in struct {
+		Pubkeys   []string
+		Messages  []string
+		Signature string
+	}
+	if err := input.Decode(&in); err != nil {
+		return err
+	}
+	messages, err := consensusHexList(in.Messages)
+	if err != nil {
+		return err
+	}
+	publicKeys, err := consensusPublicKeys(suite, in.Pubkeys)
+	if err != nil {
+		return expectBool(output, false)
+	}
+	signature, err := consensusSignature(suite, in.Signature)
+	if err != nil {
+		return expectBool(output, false)
+	}
+	return expectBool(output, suite.AggregateVerify(signature, publicKeys, messages))
+}
+
+func consensusEthAggregatePubkeys(suite *Suite, input *yaml.Node, output *yaml.Node) error {
+	var in []string
+	if err := input.Decode(&in); err != nil {
+		return err
+	}
+	publicKeys, err := consensusPublicKeys(suite, in)
+	if err != nil {
+		return expectBytes(output, nil)
+	}
+	publicKey, err := suite.EthAggregatePublicKeys(publicKeys)
+	if err != nil {
+		return expectBytes(output, nil)
+	}
+	return expectBytes(output, publicKey.ToBytes())
+}
+
+func consensusEthFastAggregateVerify(suite *Suite, input *yaml.Node, output *yaml.Node) error {
+	var in struct {
+		Pubkeys   []string
+		Message   string
+		Signature string
+	}
+	if err := input.Decode(&in); err != nil {
+		return err
+	}
+	message, err := consensusHex(in.Message)
+	if err != nil {
+		return err
+	}
+	publicKeys, err := consensusPublicKeys(suite, in.Pubkeys)
+	if err != nil {
+		return expectBool(output, false)
+	}
+	signature, err := consensusSignature(suite, in.Signature)
+	if err != nil {
+		return expectBool(output, false)
+	}
+	return expectBool(output, suite.EthFastAggregateVerify(signature, publicKeys, message))
+}
+
+func consensusBatchVerify(suite *Suite, input *yaml.Node, output *yaml.Node) error {
+	var in struct {
+		Pubkeys    []string
+		Messages   []string
+		Signatures []string
+	}
+	if err := input.Decode(&in); err != nil {
+		return err
+	}
+	messages, err := consensusHexList(in.Messages)
+	if err != nil {
+		return err
+	}
+	if len(messages) != len(in.Pubkeys) || len(messages) != len(in.Signatures) {
+		return expectBool(output, false)
+	}
+	publicKeys, err := consensusPublicKeys(suite, in.Pubkeys)
+	if err != nil {
+		return expectBool(output, false)
+	}
+	sets := make([]SignatureSet, len(messages))
+	for i := 0; i < len(sets); i++ {
+		signature, err := consensusSignature(suite, in.Signatures[i])
+		if err != nil {
+			return expectBool(output, false)
+		}
+		sets[i] = SignatureSet{publicKeys[i], messages[i], signature}
+	}
+	ok, err := suite.VerifyMultipleSignatures(sets)
+	if err != nil {
+		return expectBool(output, false)
+	}
+	return expectBool(output, ok)
+}
+
+func consensusDeserializationG1(suite *Suite, input *yaml.Node, output *yaml.Node) error {
+	var in struct{ Pubkey string }
+	if err := input.Decode(&in); err != nil {
+		return err
+	}
+	publicKey, err := consensusHex(in.Pubkey)
+	if err != nil {
+		return err
+	}
+	_, err = suite.PublicKeyFromBytes(publicKey)
+	return expectBool(output, err == nil)
+}
+
+func consensusDeserializationG2(suite *Suite, input *yaml.Node, output *yaml.Node) error {
+	var in struct{ Signature string }
+	if err := input.Decode(&in); err != nil {
+		return err
+	}
+	signature, err := consensusHex(in.Signature)
+	if err != nil {
+		return err
+	}
+	_, err = suite.SignatureFromBytes(signature)
+	return expectBool(output, err == nil)
+}
+
+// consensusHashToG2 hashes messages to G2 with the backend of min-pk suites.
+func consensusHashToG2(suite *Suite, input *yaml.Node, output *yaml.Node) error {
+	hasher, ok := suite.Backend().(messageHasher)
+	if !ok || suite.Backend().Variant() != MinPublicKeySize {
+		return fmt.Errorf("%s does not hash to G2", suite.Backend().Name())
+	}
+	var in struct{ Msg string }
+	if err := input.Decode(&in); err != nil {
+		return err
+	}
+	var out struct{ X, Y string }
+	if err := output.Decode(&out); err != nil {
+		return err
+	}
+	// uncompressed encoding is x.c1 | x.c0 | y.c1 | y.c0
+	expected := []byte{}
+	for _, coordinate := range []string{out.X, out.Y} {
+		c := strings.Split(coordinate, ",")
+		if len(c) != 2 {
+			return errors.New("invalid coordinate")
+		}
+		c0, err := consensusHex(c[0])
+		if err != nil {
+			return err
+		}
+		c1, err := consensusHex(c[1])
+		if err != nil {
+			return err
+		}
+		expected = append(expected, append(c1, c0...)...)
+	}
+	M, err := hasher.hashToSignatureGroup([]byte(in.Msg), hashToG2DST)
+	if err != nil {
+		return err
+	}
+	result := M.ToUncompressed()
+	if !bytes.Equal(expected, result) {
+		return fmt.Errorf("expected %x, got %x", expected, result)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package cross_bls
+
+// Convert re-creates a secret key, public key or signature in the given backend.
+// Values are moved between backends through their canonical compressed encoding.
+func Convert(value interface{}, backend Backend) (interface{}, error) {
+	switch v := value.(type) {
+	case SecretKey:
+		return convertSecretKey(v, backend)
+	case PublicKey:
+		return convertPublicKey(v, backend)
+	case Signature:
+		return convertSignature(v, backend)
+	}
+	return nil, errUnconvertible
+}
+
+func convertSecretKey(secretKey SecretKey, backend Backend) (SecretKey, error) {
+	if secretKey == nil {
+		return nil, errUnconvertible
+	}
+	return backend.SecretKeyFromBytes(secretKey.ToBytes())
+}
+
+func convertPublicKey(publicKey PublicKey, backend Backend) (PublicKey, error) {
+	if publicKey == nil {
+		return nil, errUnconvertible
+	}
+	return backend.PublicKeyFromBytes(publicKey.ToBytes())
+}
+
+func convertSignature(signature Signature, backend Backend) (Signature, error) {
+	if signature == nil {
+		return nil, errUnconvertible
+	}
+	return backend.SignatureFromBytes(signature.ToBytes())
+}
+
+// convertTo returns value as the type T of backend, converting it when it belongs to
+// another backend.
+func convertTo[T any](value interface{}, backend Backend) (T, error) {
+	if v, ok := value.(T); ok {
+		return v, nil
+	}
+	var zero T
+	converted, err := Convert(value, backend)
+	if err != nil {
+		return zero, err
+	}
+	v, ok := converted.(T)
+	if !ok {
+		return zero, errUnconvertible
+	}
+	return v, nil
+}
+
+// convertPoints converts values to the type T of backend and returns their points.
+func convertPoints[V, T, P any](values []V, backend Backend, point func(T) P) ([]P, error) {
+	points := make([]P, len(values))
+	for i := 0; i < len(values); i++ {
+		v, err := convertTo[T](values[i], backend)
+		if err != nil {
+			return nil, err
+		}
+		points[i] = point(v)
+	}
+	return points, nil
+}
@@ -0,0 +1,73 @@
+package cross_bls
+
+import (
+	"math/big"
+)
+
+// Arithmetic of BLS12-381 base field and its quadratic extension, used where encodings are
+// checked independently of the backends.
+
+// fieldModulus is the modulus of the base field of BLS12-381.
+var fieldModulus, _ = new(big.Int).SetString("1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab", 16)
+
+const fpByteSize = 48
+
+// fieldElements parses the big endian field elements of an encoded point, ignoring the flag bits
+// of the first byte. Coefficient of u comes first in elements of the quadratic extension.
+// It returns false if an element is not less than the modulus.
+func fieldElements(encoded []byte) ([]*big.Int, bool) {
+	elements := make([]*big.Int, len(encoded)/fpByteSize)
+	for i := range elements {
+		chunk := encoded[i*fpByteSize : (i+1)*fpByteSize]
+		if i == 0 {
+			chunk = append([]byte{chunk[0] & 0x1f}, chunk[1:]...)
+		}
+		elements[i] = new(big.Int).SetBytes(chunk)
+		if elements[i].Cmp(fieldModulus) >= 0 {
+			return nil, false
+		}
+	}
+	return elements, true
+}
+
+// g1Curve returns x^3 + 4, the right hand side of G1 curve equation.
+func g1Curve(x *big.Int) *big.Int {
+	rhs := new(big.Int).Mul(x, x)
+	rhs.Mul(rhs, x).Add(rhs, big.NewInt(4))
+	return rhs.Mod(rhs, fieldModulus)
+}
+
+// g2Curve returns x^3 + 4(u + 1), the right hand side of G2 curve equation.
+func g2Curve(x fp2) fp2 {
+	rhs := x.mul(x).mul(x)
+	four := big.NewInt(4)
+	return fp2{
+		new(big.Int).Mod(new(big.Int).Add(rhs[0], four), fieldModulus),
+		new(big.Int).Mod(new(big.Int).Add(rhs[1], four), fieldModulus),
+	}
+}
+
+// isSquare returns true if a has a square root in the base field.
+func isSquare(a *big.Int) bool {
+	exponent := new(big.Int).Rsh(fieldModulus, 1)
+	return a.Sign() == 0 || new(big.Int).Exp(a, exponent, fieldModulus).Cmp(big.NewInt(1)) == 0
+}
+
+// fp2 is an element c0 + c1 * u of the quadratic extension with u^2 = -1.
+type fp2 [2]*big.Int
+
+func (a fp2) mul(b fp2) fp2 {
+	c0 := new(big.Int).Sub(new(big.Int).Mul(a[0], b[0]), new(big.Int).Mul(a[1], b[1]))
+	c1 := new(big.Int).Add(new(big.Int).Mul(a[0], b[1]), new(big.Int).Mul(a[1], b[0]))
+	return fp2{c0.Mod(c0, fieldModulus), c1.Mod(c1, fieldModulus)}
+}
+
+func (a fp2) equal(b fp2) bool {
+	return a[0].Cmp(b[0]) == 0 && a[1].Cmp(b[1]) == 0
+}
+
+// norm returns c0^2 + c1^2, which is a square in the base field iff a is a square.
+func (a fp2) norm() *big.Int {
+	n := new(big.Int).Add(new(big.Int).Mul(a[0], a[0]), new(big.Int).Mul(a[1], a[1]))
+	return n.Mod(n, fieldModulus)
+}
@@ -0,0 +1,456 @@
+// Package dkg generates threshold BLS keys without a trusted dealer.
+//
+// The protocol is Pedersen's joint Feldman verifiable secret sharing. Every participant deals
+// a random secret with Feldman commitments in G1 and shares encrypted to other participants,
+// complains about shares that do not match the commitments and finally sums shares of qualified
+// dealers into its secret key share. Group public key is the sum of committed secrets.
+//
+// Keys are in the variant of the suite that participants run with, so identity keys of participants,
+// commitments, the group public key and public key shares are G1 points in minimal public key size
+// variant and G2 points in minimal signature size variant.
+package dkg
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"sort"
+
+	cross_bls "github.com/kilic/bls12cross/bls"
+)
+
+var groupOrder = cross_bls.GroupOrder()
+
+var shareKeyTag = []byte("BLS12CROSS_DKG_SHARE_KEY_")
+
+const scalarSize = 32
+
+var (
+	errInvalidThreshold     = errors.New("invalid threshold")
+	errInvalidIndex         = errors.New("invalid participant index")
+	errInvalidPublicKey     = errors.New("invalid participant public key")
+	errInvalidSecretKey     = errors.New("invalid participant secret key")
+	errUnknownParticipant   = errors.New("unknown participant")
+	errInvalidDeal          = errors.New("invalid deal")
+	errShareDecryption      = errors.New("share cannot be decrypted")
+	errNotDealt             = errors.New("deals are not processed")
+	errAlreadyDealt         = errors.New("deals are already processed")
+	errNotEnoughQualified   = errors.New("not enough qualified dealers")
+	errParticipantsMismatch = errors.New("participants mismatch")
+)
+
+// Deal is broadcasted by a dealer. It holds commitments to the coefficients of dealer's
+// polynomial and shares of the polynomial encrypted to each participant.
+type Deal struct {
+	Dealer      uint32
+	Commitments [][]byte
+	Shares      map[uint32][]byte
+}
+
+// Complaint is broadcasted by a participant which share from dealer is invalid.
+type Complaint struct {
+	Accuser uint32
+	Dealer  uint32
+}
+
+// Response is broadcasted by a dealer against a complaint and reveals the share of accuser.
+type Response struct {
+	Dealer  uint32
+	Accuser uint32
+	Share   []byte
+}
+
+// Result is the outcome of the protocol for a participant.
+type Result struct {
+	// Index is the index of the participant, which is also the index of its key share.
+	Index uint32
+	// SecretKeyShare is the threshold key share of the participant.
+	SecretKeyShare cross_bls.SecretKey
+	// PublicKey is the group public key.
+	PublicKey cross_bls.PublicKey
+	// PublicKeyShares are public keys of key shares of all participants.
+	PublicKeyShares map[uint32]cross_bls.PublicKey
+	// Qualified are sorted indices of dealers contributed to the group key.
+	Qualified []uint32
+}
+
+// Participant runs the protocol for one member of the group.
+// Participants are identified with their indices and BLS public keys,
+// which are used to encrypt shares to each other.
+type Participant struct {
+	suite       *cross_bls.Suite
+	index       uint32
+	secretKey   *big.Int
+	identities  map[uint32]cross_bls.PublicKey
+	threshold   int
+	session     []byte
+	polynomial  []*big.Int
+	commitments map[uint32][]cross_bls.PublicKey
+	shares      map[uint32]*big.Int
+	dealt       bool
+}
+
+// NewParticipant returns a participant at index with its identity secret key, which runs the
+// protocol with suite. Public keys are the identity keys of all participants including itself,
+// keyed with non zero indices, and are decoded with validation options of suite. Any threshold
+// many key shares will be able to sign for the group. Session separates share encryption keys
+// of different runs among the same participants.
+func NewParticipant(suite *cross_bls.Suite, index uint32, secretKey cross_bls.SecretKey, publicKeys map[uint32]cross_bls.PublicKey, threshold int, session []byte) (*Participant, error) {
+	if threshold < 1 || threshold > len(publicKeys) {
+		return nil, errInvalidThreshold
+	}
+	if secretKey == nil {
+		return nil, errInvalidSecretKey
+	}
+	identities := make(map[uint32]cross_bls.PublicKey, len(publicKeys))
+	for i, publicKey := range publicKeys {
+		if i == 0 {
+			return nil, errInvalidIndex
+		}
+		if publicKey == nil {
+			return nil, errInvalidPublicKey
+		}
+		identity, err := suite.PublicKeyFromBytes(publicKey.ToBytes())
+		if err != nil {
+			return nil, errInvalidPublicKey
+		}
+		identities[i] = identity
+	}
+	if _, ok := identities[index]; !ok {
+		return nil, errUnknownParticipant
+	}
+	if !publicKeys[index].Equal(secretKey.PublicKey()) {
+		return nil, errInvalidPublicKey
+	}
+	return &Participant{
+		suite:       suite,
+		index:       index,
+		secretKey:   new(big.Int).SetBytes(secretKey.ToBytes()),
+		identities:  identities,
+		threshold:   threshold,
+		session:     append([]byte{}, session...),
+		commitments: make(map[uint32][]cross_bls.PublicKey),
+		shares:      make(map[uint32]*big.Int),
+	}, nil
+}
+
+// Index returns the index of the participant.
+func (participant *Participant) Index() uint32 {
+	return participant.index
+}
+
+// Deal samples the secret polynomial of the participant and returns the deal to be broadcasted.
+func (participant *Participant) Deal() (*Deal, error) {
+	if participant.polynomial == nil {
+		polynomial := make([]*big.Int, participant.threshold)
+		for i := 0; i < len(polynomial); i++ {
+			// coefficients are not zero, so commitments are not the point at infinity
+			coefficient, err := rand.Int(rand.Reader, new(big.Int).Sub(groupOrder, big.NewInt(1)))
+			if err != nil {
+				return nil, err
+			}
+			polynomial[i] = coefficient.Add(coefficient, big.NewInt(1))
+		}
+		participant.polynomial = polynomial
+	}
+	deal := &Deal{
+		Dealer:      participant.index,
+		Commitments: make([][]byte, len(participant.polynomial)),
+		Shares:      make(map[uint32][]byte, len(participant.identities)),
+	}
+	for i, coefficient := range participant.polynomial {
+		secretKey, err := participant.suite.SecretKeyFromBytes(scalarToBytes(coefficient))
+		if err != nil {
+			return nil, err
+		}
+		deal.Commitments[i] = secretKey.PublicKey().ToBytes()
+	}
+	for j := range participant.identities {
+		share := scalarToBytes(cross_bls.EvaluatePolynomial(participant.polynomial, j))
+		encrypted, err := participant.seal(participant.index, j, share)
+		if err != nil {
+			return nil, err
+		}
+		deal.Shares[j] = encrypted
+	}
+	return deal, nil
+}
+
+// ProcessDeals verifies shares of participant in deals against their commitments and
+// returns complaints against dealers which shares are invalid. Malformed deals and
+// participants that do not deal are disqualified without complaints.
+func (participant *Participant) ProcessDeals(deals []*Deal) ([]*Complaint, error) {
+	if participant.dealt {
+		return nil, errAlreadyDealt
+	}
+	participant.dealt = true
+	complaints := []*Complaint{}
+	for _, deal := range deals {
+		if deal == nil {
+			continue
+		}
+		if _, ok := participant.identities[deal.Dealer]; !ok {
+			continue
+		}
+		if _, ok := participant.commitments[deal.Dealer]; ok {
+			// a dealer that deals twice is disqualified
+			delete(participant.shares, deal.Dealer)
+			participant.commitments[deal.Dealer] = nil
+			continue
+		}
+		commitments, err := participant.decodeDeal(deal)
+		if err != nil {
+			participant.commitments[deal.Dealer] = nil
+			continue
+		}
+		participant.commitments[deal.Dealer] = commitments
+		share, err := participant.open(deal.Dealer, participant.index, deal.Shares[participant.index])
+		if err == nil && participant.verifyShare(commitments, participant.index, share) {
+			participant.shares[deal.Dealer] = share
+			continue
+		}
+		complaints = append(complaints, &Complaint{Accuser: participant.index, Dealer: deal.Dealer})
+	}
+	return complaints, nil
+}
+
+// Respond reveals shares of accusers in complaints against the participant.
+func (participant *Participant) Respond(complaints []*Complaint) []*Response {
+	responses := []*Response{}
+	if participant.polynomial == nil {
+		return responses
+	}
+	responded := make(map[uint32]bool)
+	for _, complaint := range complaints {
+		if complaint == nil || complaint.Dealer != participant.index || responded[complaint.Accuser] {
+			continue
+		}
+		if _, ok := participant.identities[complaint.Accuser]; !ok {
+			continue
+		}
+		responded[complaint.Accuser] = true
+		responses = append(responses, &Response{
+			Dealer:  participant.index,
+			Accuser: complaint.Accuser,
+			Share:   scalarToBytes(cross_bls.EvaluatePolynomial(participant.polynomial, complaint.Accuser)),
+		})
+	}
+	return responses
+}
+
+// Finalize resolves complaints with responses, determines qualified dealers and
+// derives the key share of the participant and the group public key. A dealer is
+// disqualified if a complaint against it is not answered with a share that matches
+// its commitments.
+func (participant *Participant) Finalize(complaints []*Complaint, responses []*Response) (*Result, error) {
+	if !participant.dealt {
+		return nil, errNotDealt
+	}
+	disqualified := make(map[uint32]bool)
+	for dealer, commitments := range participant.commitments {
+		if commitments == nil {
+			disqualified[dealer] = true
+		}
+	}
+	for dealer := range participant.identities {
+		if _, ok := participant.commitments[dealer]; !ok {
+			disqualified[dealer] = true
+		}
+	}
+	for _, complaint := range complaints {
+		if complaint == nil || disqualified[complaint.Dealer] {
+			continue
+		}
+		if _, ok := participant.identities[complaint.Accuser]; !ok {
+			continue
+		}
+		share, ok := participant.resolve(complaint, responses)
+		if !ok {
+			disqualified[complaint.Dealer] = true
+			continue
+		}
+		if complaint.Accuser == participant.index {
+			participant.shares[complaint.Dealer] = share
+		}
+	}
+	qualified := []uint32{}
+	for dealer := range participant.identities {
+		if !disqualified[dealer] {
+			qualified = append(qualified, dealer)
+		}
+	}
+	sort.Slice(qualified, func(i, j int) bool { return qualified[i] < qualified[j] })
+	if len(qualified) < participant.threshold {
+		return nil, errNotEnoughQualified
+	}
+	// secret key share is the sum of shares and the group commitments are sum of
+	// commitments of qualified dealers
+	secretKeyShare := new(big.Int)
+	for _, dealer := range qualified {
+		share, ok := participant.shares[dealer]
+		if !ok {
+			return nil, errNotEnoughQualified
+		}
+		secretKeyShare.Add(secretKeyShare, share).Mod(secretKeyShare, groupOrder)
+	}
+	commitments := make([]cross_bls.PublicKey, participant.threshold)
+	for i := 0; i < len(commitments); i++ {
+		terms := make([]cross_bls.PublicKey, len(qualified))
+		for k, dealer := range qualified {
+			terms[k] = participant.commitments[dealer][i]
+		}
+		commitment, err := participant.suite.AggregatePublicKeys(terms)
+		if err != nil {
+			return nil, err
+		}
+		commitments[i] = commitment
+	}
+	_secretKeyShare, err := participant.suite.SecretKeyFromBytes(scalarToBytes(secretKeyShare))
+	if err != nil {
+		return nil, err
+	}
+	publicKey, err := participant.suite.PublicKeyFromBytes(commitments[0].ToBytes())
+	if err != nil {
+		return nil, err
+	}
+	publicKeyShares := make(map[uint32]cross_bls.PublicKey, len(participant.identities))
+	for j := range participant.identities {
+		publicKeyShare, err := participant.suite.EvaluateCommitments(commitments, j)
+		if err != nil {
+			return nil, err
+		}
+		publicKeyShares[j] = publicKeyShare
+	}
+	if !publicKeyShares[participant.index].Equal(_secretKeyShare.PublicKey()) {
+		return nil, errParticipantsMismatch
+	}
+	return &Result{
+		Index:           participant.index,
+		SecretKeyShare:  _secretKeyShare,
+		PublicKey:       publicKey,
+		PublicKeyShares: publicKeyShares,
+		Qualified:       qualified,
+	}, nil
+}
+
+// resolve looks for a response of the accused dealer that reveals a valid share of accuser.
+func (participant *Participant) resolve(complaint *Complaint, responses []*Response) (*big.Int, bool) {
+	for _, response := range responses {
+		if response == nil || response.Dealer != complaint.Dealer || response.Accuser != complaint.Accuser {
+			continue
+		}
+		if len(response.Share) != scalarSize {
+			continue
+		}
+		share := new(big.Int).SetBytes(response.Share)
+		if share.Cmp(groupOrder) >= 0 {
+			continue
+		}
+		if participant.verifyShare(participant.commitments[complaint.Dealer], complaint.Accuser, share) {
+			return share, true
+		}
+	}
+	return nil, false
+}
+
+func (participant *Participant) decodeDeal(deal *Deal) ([]cross_bls.PublicKey, error) {
+	if len(deal.Commitments) != participant.threshold {
+		return nil, errInvalidDeal
+	}
+	commitments := make([]cross_bls.PublicKey, len(deal.Commitments))
+	for i, commitment := range deal.Commitments {
+		point, err := participant.suite.PublicKeyFromBytes(commitment)
+		if err != nil {
+			return nil, errInvalidDeal
+		}
+		commitments[i] = point
+	}
+	return commitments, nil
+}
+
+// shareKey derives the symmetric key between dealer and recipient from their
+// Diffie-Hellman secret on identity keys.
+func (participant *Participant) shareKey(dealer, recipient uint32) ([]byte, error) {
+	other := recipient
+	if other == participant.index {
+		other = dealer
+	}
+	identity, ok := participant.identities[other]
+	if !ok {
+		return nil, errUnknownParticipant
+	}
+	secret, err := participant.suite.MulPublicKey(identity, participant.secretKey)
+	if err != nil {
+		return nil, err
+	}
+	indices := make([]byte, 8)
+	binary.BigEndian.PutUint32(indices[:4], dealer)
+	binary.BigEndian.PutUint32(indices[4:], recipient)
+	h := sha256.New()
+	h.Write(shareKeyTag)
+	h.Write(participant.session)
+	h.Write(indices)
+	h.Write(secret.ToBytes())
+	return h.Sum(nil), nil
+}
+
+func (participant *Participant) seal(dealer, recipient uint32, share []byte) ([]byte, error) {
+	aead, err := participant.aead(dealer, recipient)
+	if err != nil {
+		return nil, err
+	}
+	// keys are used once per session, dealer and recipient
+	return aead.Seal(nil, make([]byte, aead.NonceSize()), share, nil), nil
+}
+
+func (participant *Participant) open(dealer, recipient uint32, encrypted []byte) (*big.Int, error) {
+	aead, err := participant.aead(dealer, recipient)
+	if err != nil {
+		return nil, err
+	}
+	share, err := aead.Open(nil, make([]byte, aead.NonceSize()), encrypted, nil)
+	if err != nil || len(share) != scalarSize {
+		return nil, errShareDecryption
+	}
+	_share := new(big.Int).SetBytes(share)
+	if _share.Cmp(groupOrder) >= 0 {
+		return nil, errShareDecryption
+	}
+	return _share, nil
+}
+
+func (participant *Participant) aead(dealer, recipient uint32) (cipher.AEAD, error) {
+	key, err := participant.shareKey(dealer, recipient)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// verifyShare checks share * g == C_0 + C_1 * x + ... + C_(t-1) * x^(t-1) where g is
+// the generator of public keys of the suite.
+func (participant *Participant) verifyShare(commitments []cross_bls.PublicKey, x uint32, share *big.Int) bool {
+	secretKey, err := participant.suite.SecretKeyFromBytes(scalarToBytes(share))
+	if err != nil {
+		return false
+	}
+	publicKey, err := participant.suite.EvaluateCommitments(commitments, x)
+	if err != nil {
+		return false
+	}
+	return publicKey.Equal(secretKey.PublicKey())
+}
+
+func scalarToBytes(e *big.Int) []byte {
+	out := make([]byte, scalarSize)
+	e.FillBytes(out)
+	return out
+}
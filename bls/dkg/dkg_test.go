@@ -0,0 +1,236 @@
+package dkg
+
+import (
+	"bytes"
+	"flag"
+	"math/big"
+	"reflect"
+	"testing"
+
+	cross_bls "github.com/kilic/bls12cross/bls"
+)
+
+var library = flag.String("lib", "none", "select a library")
+
+// newSuites returns proof of possession suites of every registered backend, or of the one selected with -lib.
+func newSuites(t *testing.T) []*cross_bls.Suite {
+	names := cross_bls.Backends()
+	if *library != "none" {
+		names = []string{*library}
+	}
+	suites := []*cross_bls.Suite{}
+	for _, name := range names {
+		backend, err := cross_bls.Lookup(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		suite, err := cross_bls.NewCiphersuite(backend, cross_bls.PoP, cross_bls.ValidationOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		suites = append(suites, suite)
+	}
+	return suites
+}
+
+var session = []byte("test session")
+
+// tamperTransport lets tests to modify messages of participants before they are broadcasted.
+type tamperTransport struct {
+	*MemoryTransport
+	deal      func(deal *Deal) *Deal
+	responses func(responses []*Response) []*Response
+}
+
+func (transport *tamperTransport) BroadcastDeal(deal *Deal) error {
+	if transport.deal != nil {
+		deal = transport.deal(deal)
+		if deal == nil {
+			return nil
+		}
+	}
+	return transport.MemoryTransport.BroadcastDeal(deal)
+}
+
+func (transport *tamperTransport) BroadcastResponses(responses []*Response) error {
+	if transport.responses != nil {
+		responses = transport.responses(responses)
+	}
+	return transport.MemoryTransport.BroadcastResponses(responses)
+}
+
+func newParticipants(t *testing.T, suite *cross_bls.Suite, n, threshold int) []*Participant {
+	secretKeys := make(map[uint32]cross_bls.SecretKey)
+	publicKeys := make(map[uint32]cross_bls.PublicKey)
+	for i := 1; i <= n; i++ {
+		secretKeys[uint32(i)] = suite.RandSecretKey()
+		publicKeys[uint32(i)] = secretKeys[uint32(i)].PublicKey()
+	}
+	participants := make([]*Participant, n)
+	for i := 1; i <= n; i++ {
+		participant, err := NewParticipant(suite, uint32(i), secretKeys[uint32(i)], publicKeys, threshold, session)
+		if err != nil {
+			t.Fatal(err)
+		}
+		participants[i-1] = participant
+	}
+	return participants
+}
+
+// checkResults checks that participants agree on the group key and that
+// any threshold many key shares sign for the group.
+func checkResults(t *testing.T, suite *cross_bls.Suite, results []*Result, threshold int, qualified []uint32) {
+	message := []byte("test")
+	for _, result := range results {
+		if !result.PublicKey.Equal(results[0].PublicKey) {
+			t.Fatalf("participant %d group public key", result.Index)
+		}
+		if !reflect.DeepEqual(qualified, result.Qualified) {
+			t.Fatalf("participant %d qualified dealers %v", result.Index, result.Qualified)
+		}
+		for index, publicKeyShare := range results[0].PublicKeyShares {
+			if !publicKeyShare.Equal(result.PublicKeyShares[index]) {
+				t.Fatalf("participant %d public key share %d", result.Index, index)
+			}
+		}
+		if !result.SecretKeyShare.PublicKey().Equal(result.PublicKeyShares[result.Index]) {
+			t.Fatalf("participant %d secret key share", result.Index)
+		}
+	}
+	publicKey, err := suite.RecoverPublicKey(results[0].PublicKeyShares)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !publicKey.Equal(results[0].PublicKey) {
+		t.Fatalf("recovered public key")
+	}
+	var expected []byte
+	for i := 0; i+threshold <= len(results); i++ {
+		partials := make(map[uint32]cross_bls.Signature)
+		for _, result := range results[i : i+threshold] {
+			partials[result.Index] = suite.Sign(result.SecretKeyShare, message)
+		}
+		signature, err := suite.RecoverSignature(partials)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !suite.Verify(signature, results[0].PublicKey, message) {
+			t.Fatalf("recovered signature must be verified")
+		}
+		if expected == nil {
+			expected = signature.ToBytes()
+		} else if !bytes.Equal(expected, signature.ToBytes()) {
+			t.Fatalf("recovered signatures must be equal")
+		}
+	}
+}
+
+func TestDKG(t *testing.T) {
+	for _, suite := range newSuites(t) {
+		for _, c := range []struct{ n, threshold int }{{1, 1}, {3, 2}, {5, 3}, {7, 7}} {
+			participants := newParticipants(t, suite, c.n, c.threshold)
+			results, err := Run(participants, NewMemoryTransport())
+			if err != nil {
+				t.Fatal(err)
+			}
+			qualified := []uint32{}
+			for i := 1; i <= c.n; i++ {
+				qualified = append(qualified, uint32(i))
+			}
+			checkResults(t, suite, results, c.threshold, qualified)
+		}
+	}
+}
+
+func TestDKGComplaint(t *testing.T) {
+	for _, suite := range newSuites(t) {
+		// share of participant 4 from dealer 2 is corrupted, dealer reveals the valid share
+		participants := newParticipants(t, suite, 5, 3)
+		transport := &tamperTransport{MemoryTransport: NewMemoryTransport()}
+		transport.deal = func(deal *Deal) *Deal {
+			if deal.Dealer == 2 {
+				deal.Shares[4][0] ^= 1
+			}
+			return deal
+		}
+		results, err := Run(participants, transport)
+		if err != nil {
+			t.Fatal(err)
+		}
+		complaints, _ := transport.Complaints()
+		if len(complaints) != 1 || complaints[0].Dealer != 2 || complaints[0].Accuser != 4 {
+			t.Fatalf("expected a complaint against dealer 2")
+		}
+		checkResults(t, suite, results, 3, []uint32{1, 2, 3, 4, 5})
+	}
+}
+
+func TestDKGDisqualification(t *testing.T) {
+	for _, suite := range newSuites(t) {
+		// dealer 3 sends an invalid share and does not justify it, dealer 5 does not deal
+		participants := newParticipants(t, suite, 5, 3)
+		transport := &tamperTransport{MemoryTransport: NewMemoryTransport()}
+		transport.deal = func(deal *Deal) *Deal {
+			switch deal.Dealer {
+			case 3:
+				encrypted, err := participants[2].seal(3, 1, scalarToBytes(big.NewInt(1)))
+				if err != nil {
+					t.Fatal(err)
+				}
+				deal.Shares[1] = encrypted
+			case 5:
+				return nil
+			}
+			return deal
+		}
+		transport.responses = func(responses []*Response) []*Response {
+			for _, response := range responses {
+				if response.Dealer == 3 {
+					response.Share[31] ^= 1
+				}
+			}
+			return responses
+		}
+		results, err := Run(participants, transport)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkResults(t, suite, results, 3, []uint32{1, 2, 4})
+	}
+}
+
+func TestDKGNotEnoughQualified(t *testing.T) {
+	for _, suite := range newSuites(t) {
+		participants := newParticipants(t, suite, 3, 3)
+		transport := &tamperTransport{MemoryTransport: NewMemoryTransport()}
+		transport.deal = func(deal *Deal) *Deal {
+			if deal.Dealer == 1 {
+				deal.Commitments = deal.Commitments[:2]
+			}
+			return deal
+		}
+		if _, err := Run(participants, transport); err != errNotEnoughQualified {
+			t.Fatalf("expected not enough qualified dealers")
+		}
+	}
+}
+
+func TestNewParticipant(t *testing.T) {
+	for _, suite := range newSuites(t) {
+		secretKey := suite.RandSecretKey()
+		publicKeys := map[uint32]cross_bls.PublicKey{1: secretKey.PublicKey(), 2: suite.RandSecretKey().PublicKey()}
+		if _, err := NewParticipant(suite, 1, secretKey, publicKeys, 3, session); err != errInvalidThreshold {
+			t.Fatalf("threshold must not exceed number of participants")
+		}
+		if _, err := NewParticipant(suite, 3, secretKey, publicKeys, 2, session); err != errUnknownParticipant {
+			t.Fatalf("index must be of a participant")
+		}
+		if _, err := NewParticipant(suite, 2, secretKey, publicKeys, 2, session); err != errInvalidPublicKey {
+			t.Fatalf("secret key must match the public key of the participant")
+		}
+		publicKeys[0] = suite.RandSecretKey().PublicKey()
+		if _, err := NewParticipant(suite, 1, secretKey, publicKeys, 2, session); err != errInvalidIndex {
+			t.Fatalf("zero index must be rejected")
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package dkg
+
+import (
+	"sync"
+)
+
+// Transport broadcasts protocol messages to all participants. Transports are expected
+// to authenticate senders of messages, and to deliver the same messages to every participant.
+type Transport interface {
+	BroadcastDeal(deal *Deal) error
+	BroadcastComplaints(complaints []*Complaint) error
+	BroadcastResponses(responses []*Response) error
+	Deals() ([]*Deal, error)
+	Complaints() ([]*Complaint, error)
+	Responses() ([]*Response, error)
+}
+
+// MemoryTransport is an in memory transport where all participants run in the same process.
+type MemoryTransport struct {
+	mu         sync.Mutex
+	deals      []*Deal
+	complaints []*Complaint
+	responses  []*Response
+}
+
+// NewMemoryTransport returns an empty in memory transport.
+func NewMemoryTransport() *MemoryTransport {
+	return &MemoryTransport{}
+}
+
+func (transport *MemoryTransport) BroadcastDeal(deal *Deal) error {
+	transport.mu.Lock()
+	defer transport.mu.Unlock()
+	transport.deals = append(transport.deals, deal)
+	return nil
+}
+
+func (transport *MemoryTransport) BroadcastComplaints(complaints []*Complaint) error {
+	transport.mu.Lock()
+	defer transport.mu.Unlock()
+	transport.complaints = append(transport.complaints, complaints...)
+	return nil
+}
+
+func (transport *MemoryTransport) BroadcastResponses(responses []*Response) error {
+	transport.mu.Lock()
+	defer transport.mu.Unlock()
+	transport.responses = append(transport.responses, responses...)
+	return nil
+}
+
+func (transport *MemoryTransport) Deals() ([]*Deal, error) {
+	transport.mu.Lock()
+	defer transport.mu.Unlock()
+	return append([]*Deal{}, transport.deals...), nil
+}
+
+func (transport *MemoryTransport) Complaints() ([]*Complaint, error) {
+	transport.mu.Lock()
+	defer transport.mu.Unlock()
+	return append([]*Complaint{}, transport.complaints...), nil
+}
+
+func (transport *MemoryTransport) Responses() ([]*Response, error) {
+	transport.mu.Lock()
+	defer transport.mu.Unlock()
+	return append([]*Response{}, transport.responses...), nil
+}
+
+// Run drives participants through deal, complaint and response rounds over transport
+// and returns results of participants in the given order.
+func Run(participants []*Participant, transport Transport) ([]*Result, error) {
+	for _, participant := range participants {
+		deal, err := participant.Deal()
+		if err != nil {
+			return nil, err
+		}
+		if err := transport.BroadcastDeal(deal); err != nil {
+			return nil, err
+		}
+	}
+	deals, err := transport.Deals()
+	if err != nil {
+		return nil, err
+	}
+	for _, participant := range participants {
+		complaints, err := participant.ProcessDeals(deals)
+		if err != nil {
+			return nil, err
+		}
+		if err := transport.BroadcastComplaints(complaints); err != nil {
+			return nil, err
+		}
+	}
+	complaints, err := transport.Complaints()
+	if err != nil {
+		return nil, err
+	}
+	for _, participant := range participants {
+		if err := transport.BroadcastResponses(participant.Respond(complaints)); err != nil {
+			return nil, err
+		}
+	}
+	responses, err := transport.Responses()
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*Result, len(participants))
+	for i, participant := range participants {
+		result, err := participant.Finalize(complaints, responses)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = result
+	}
+	return results, nil
+}
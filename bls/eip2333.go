@@ -0,0 +1,168 @@
+package cross_bls
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// Hierarchical key derivation of EIP-2333 and key paths of EIP-2334.
+// Derived keys are independent of the backend, so suites of every backend derive the same keys.
+
+var (
+	errShortSeed   = errors.New("seed must be at least 32 bytes")
+	errInvalidPath = errors.New("invalid key path")
+)
+
+// groupOrder is the order of BLS12-381 G1 and G2 subgroups.
+var groupOrder, _ = new(big.Int).SetString("73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001", 16)
+
+const eip2334Purpose = 12381
+const eip2334CoinType = 3600
+
+// DeriveMasterSK derives the master secret key from seed as described in EIP-2333.
+func DeriveMasterSK(seed []byte) (SecretKey, error) {
+	return currentSuite().DeriveMasterSK(seed)
+}
+
+// DeriveChildSK derives the child secret key at index of parent as described in EIP-2333.
+func DeriveChildSK(parent SecretKey, index uint32) (SecretKey, error) {
+	return currentSuite().DeriveChildSK(parent, index)
+}
+
+// DeriveSKFromPath derives the secret key at EIP-2334 path from seed.
+func DeriveSKFromPath(seed []byte, path string) (SecretKey, error) {
+	return currentSuite().DeriveSKFromPath(seed, path)
+}
+
+func (suite *Suite) DeriveMasterSK(seed []byte) (SecretKey, error) {
+	if len(seed) < 32 {
+		return nil, errShortSeed
+	}
+	return suite.secretKeyFromBig(hkdfModR(seed, nil))
+}
+
+func (suite *Suite) DeriveChildSK(parent SecretKey, index uint32) (SecretKey, error) {
+	if parent == nil {
+		return nil, errInvalidSecretKey
+	}
+	lamportPublicKey := parentSKToLamportPK(parent.ToBytes(), index)
+	return suite.secretKeyFromBig(hkdfModR(lamportPublicKey, nil))
+}
+
+func (suite *Suite) DeriveSKFromPath(seed []byte, path string) (SecretKey, error) {
+	indices, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	secretKey, err := suite.DeriveMasterSK(seed)
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range indices {
+		secretKey, err = suite.DeriveChildSK(secretKey, index)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return secretKey, nil
+}
+
+func (suite *Suite) secretKeyFromBig(secretKey *big.Int) (SecretKey, error) {
+	out := make([]byte, SecretKeySize)
+	secretKey.FillBytes(out)
+	return suite.SecretKeyFromBytes(out)
+}
+
+// ParsePath parses an EIP-2334 key path such as m/12381/3600/0/0/0 into child indices.
+// Paths start with purpose 12381 and coin type 3600 of BLS12-381 keys of Ethereum.
+func ParsePath(path string) ([]uint32, error) {
+	nodes := strings.Split(path, "/")
+	if nodes[0] != "m" {
+		return nil, errInvalidPath
+	}
+	indices := make([]uint32, len(nodes)-1)
+	for i, node := range nodes[1:] {
+		// only plain decimal indices are allowed
+		if node == "" || strings.TrimLeft(node, "0123456789") != "" {
+			return nil, errInvalidPath
+		}
+		index, err := strconv.ParseUint(node, 10, 32)
+		if err != nil {
+			return nil, errInvalidPath
+		}
+		indices[i] = uint32(index)
+	}
+	if len(indices) < 2 || indices[0] != eip2334Purpose || indices[1] != eip2334CoinType {
+		return nil, errInvalidPath
+	}
+	return indices, nil
+}
+
+// WithdrawalKeyPath returns EIP-2334 path of the withdrawal key of validator at index.
+func WithdrawalKeyPath(index uint32) string {
+	return "m/" + strconv.Itoa(eip2334Purpose) + "/" + strconv.Itoa(eip2334CoinType) + "/" + strconv.FormatUint(uint64(index), 10) + "/0"
+}
+
+// SigningKeyPath returns EIP-2334 path of the signing key of validator at index.
+func SigningKeyPath(index uint32) string {
+	return WithdrawalKeyPath(index) + "/0"
+}
+
+// hkdfModR derives a non zero scalar from ikm and keyInfo.
+// HKDF_mod_r is defined in EIP-2333 and draft-irtf-cfrg-bls-signature KeyGen.
+func hkdfModR(ikm, keyInfo []byte) *big.Int {
+	const L = 48
+	salt := []byte("BLS-SIG-KEYGEN-SALT-")
+	_ikm := append(append([]byte{}, ikm...), 0)
+	_keyInfo := append(append([]byte{}, keyInfo...), 0, L)
+	secretKey := new(big.Int)
+	for secretKey.Sign() == 0 {
+		h := sha256.Sum256(salt)
+		salt = h[:]
+		prk := hkdf.Extract(sha256.New, _ikm, salt)
+		okm := make([]byte, L)
+		if _, err := io.ReadFull(hkdf.Expand(sha256.New, prk, _keyInfo), okm); err != nil {
+			panic(err)
+		}
+		secretKey.SetBytes(okm).Mod(secretKey, groupOrder)
+	}
+	return secretKey
+}
+
+// ikmToLamportSK expands ikm into 255 lamport secret key chunks of 32 bytes.
+func ikmToLamportSK(ikm, salt []byte) [][]byte {
+	okm := make([]byte, 32*255)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, ikm, salt, nil), okm); err != nil {
+		panic(err)
+	}
+	lamportSecretKey := make([][]byte, 255)
+	for i := 0; i < 255; i++ {
+		lamportSecretKey[i] = okm[32*i : 32*(i+1)]
+	}
+	return lamportSecretKey
+}
+
+// parentSKToLamportPK returns the compressed lamport public key of parent at index.
+func parentSKToLamportPK(parent []byte, index uint32) []byte {
+	salt := make([]byte, 4)
+	binary.BigEndian.PutUint32(salt, index)
+	notIKM := make([]byte, len(parent))
+	for i := range parent {
+		notIKM[i] = parent[i] ^ 0xff
+	}
+	lamport0 := ikmToLamportSK(parent, salt)
+	lamport1 := ikmToLamportSK(notIKM, salt)
+	h := sha256.New()
+	for _, chunk := range append(lamport0, lamport1...) {
+		c := sha256.Sum256(chunk)
+		h.Write(c[:])
+	}
+	return h.Sum(nil)
+}
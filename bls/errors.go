@@ -0,0 +1,161 @@
+package cross_bls
+
+import (
+	"errors"
+	"math/big"
+)
+
+// Errors that classify why an encoded secret key, public key or signature is rejected.
+// Every backend maps its decoding failures into these, so the same malformed input
+// yields the same error on every backend. Errors returned by decoders wrap one of them
+// in a DecodingError and are matched with errors.Is.
+var (
+	ErrInvalidSize   = errors.New("invalid size")
+	ErrZero          = errors.New("zero value")
+	ErrInfinity      = errors.New("point at infinity")
+	ErrNotOnCurve    = errors.New("point is not on curve")
+	ErrNotInSubgroup = errors.New("point is not in correct subgroup")
+	ErrNonCanonical  = errors.New("non canonical encoding")
+	ErrInvalidFlags  = errors.New("invalid encoding flags")
+)
+
+// DecodingError is returned when an encoded value cannot be decoded.
+// Kind is either "secret key", "public key" or "signature".
+type DecodingError struct {
+	Kind string
+	Err  error
+}
+
+func (e *DecodingError) Error() string {
+	return "invalid " + e.Kind + ": " + e.Err.Error()
+}
+
+func (e *DecodingError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	errSecretKeySize         = &DecodingError{"secret key", ErrInvalidSize}
+	errZeroSecretKey         = &DecodingError{"secret key", ErrZero}
+	errSecretKeyNonCanonical = &DecodingError{"secret key", ErrNonCanonical}
+	errPublicKeySize         = &DecodingError{"public key", ErrInvalidSize}
+	errZeroPublicKey         = &DecodingError{"public key", ErrZero}
+	errInfinitePublicKey     = &DecodingError{"public key", ErrInfinity}
+	errPublicKeyFlags        = &DecodingError{"public key", ErrInvalidFlags}
+	errPublicKeyNonCanonical = &DecodingError{"public key", ErrNonCanonical}
+	errPublicKeyNotOnCurve   = &DecodingError{"public key", ErrNotOnCurve}
+	errPublicKeySubgroup     = &DecodingError{"public key", ErrNotInSubgroup}
+	errSignatureSize         = &DecodingError{"signature", ErrInvalidSize}
+	errZeroSignature         = &DecodingError{"signature", ErrZero}
+	errInfiniteSignature     = &DecodingError{"signature", ErrInfinity}
+	errSignatureFlags        = &DecodingError{"signature", ErrInvalidFlags}
+	errSignatureNonCanonical = &DecodingError{"signature", ErrNonCanonical}
+	errSignatureNotOnCurve   = &DecodingError{"signature", ErrNotOnCurve}
+	errSignatureSubgroup     = &DecodingError{"signature", ErrNotInSubgroup}
+)
+
+var publicKeyErrors = map[error]error{
+	ErrInvalidSize:   errPublicKeySize,
+	ErrZero:          errZeroPublicKey,
+	ErrInfinity:      errInfinitePublicKey,
+	ErrInvalidFlags:  errPublicKeyFlags,
+	ErrNonCanonical:  errPublicKeyNonCanonical,
+	ErrNotOnCurve:    errPublicKeyNotOnCurve,
+	ErrNotInSubgroup: errPublicKeySubgroup,
+}
+
+var signatureErrors = map[error]error{
+	ErrInvalidSize:   errSignatureSize,
+	ErrZero:          errZeroSignature,
+	ErrInfinity:      errInfiniteSignature,
+	ErrInvalidFlags:  errSignatureFlags,
+	ErrNonCanonical:  errSignatureNonCanonical,
+	ErrNotOnCurve:    errSignatureNotOnCurve,
+	ErrNotInSubgroup: errSignatureSubgroup,
+}
+
+// pointError returns the error of a point encoding that a backend failed to decode.
+// It is either publicKeyError or signatureError.
+type pointError func(encoded []byte, onG2 bool, reason error) error
+
+// publicKeyError returns the error of a public key encoding that a backend failed to decode.
+// reason is the failure that the backend confirmed, or nil if the backend did not report one.
+func publicKeyError(encoded []byte, onG2 bool, reason error) error {
+	if err, ok := publicKeyErrors[classifyPoint(encoded, onG2, reason)]; ok {
+		return err
+	}
+	return errInvalidPublicKey
+}
+
+// signatureError returns the error of a signature encoding that a backend failed to decode.
+// reason is the failure that the backend confirmed, or nil if the backend did not report one.
+func signatureError(encoded []byte, onG2 bool, reason error) error {
+	if err, ok := signatureErrors[classifyPoint(encoded, onG2, reason)]; ok {
+		return err
+	}
+	return errInvalidSignature
+}
+
+// Libraries order their encoding checks differently, so rules of the zcash serialization format
+// are checked here in a fixed order before the reason reported by the backend. blst and herumi
+// do not report why a point is rejected, so backends confirm what they can, such as a point
+// decoded on curve but out of subgroup, and pass it as reason.
+
+// classifyPoint returns the first rule of the encoding that the encoded point on G1 or G2 breaks,
+// then reason, then whether the point is not on curve. It returns nil if no failure is confirmed,
+// in which case decoders return a generic error.
+func classifyPoint(encoded []byte, onG2 bool, reason error) error {
+	degree := 1
+	if onG2 {
+		degree = 2
+	}
+	compressed := len(encoded) == degree*fpByteSize
+	if !compressed && len(encoded) != 2*degree*fpByteSize {
+		return ErrInvalidSize
+	}
+	if isZeroBytes(encoded) {
+		return ErrZero
+	}
+	if (encoded[0]&0x80 != 0) != compressed {
+		return ErrInvalidFlags
+	}
+	if encoded[0]&0x40 != 0 {
+		if encoded[0]&0x3f == 0 && isZeroBytes(encoded[1:]) {
+			return ErrInfinity
+		}
+		return ErrInvalidFlags
+	}
+	if !compressed && encoded[0]&0x20 != 0 {
+		return ErrInvalidFlags
+	}
+	elements, ok := fieldElements(encoded)
+	if !ok {
+		return ErrNonCanonical
+	}
+	if reason != nil {
+		return reason
+	}
+	var onCurve bool
+	if onG2 {
+		x := fp2{elements[1], elements[0]}
+		if compressed {
+			onCurve = isSquare(g2Curve(x).norm())
+		} else {
+			y := fp2{elements[3], elements[2]}
+			onCurve = y.mul(y).equal(g2Curve(x))
+		}
+	} else {
+		x := elements[0]
+		rhs := g1Curve(x)
+		if compressed {
+			onCurve = isSquare(rhs)
+		} else {
+			y := elements[1]
+			onCurve = new(big.Int).Mod(new(big.Int).Mul(y, y), fieldModulus).Cmp(rhs) == 0
+		}
+	}
+	if !onCurve {
+		return ErrNotOnCurve
+	}
+	return nil
+}
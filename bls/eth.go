@@ -0,0 +1,64 @@
+package cross_bls
+
+import (
+	"bytes"
+)
+
+// ethValidationOptions are always applied in ethereum flavoured functions,
+// as the consensus spec validates public keys and signatures in every verification.
+var ethValidationOptions = ValidationOptions{CheckSignatureSubgroup: true, ValidatePublicKey: true}
+
+// EthAggregatePublicKeys aggregates public keys following eth_aggregate_pubkeys of the consensus spec.
+// Empty input, public keys at infinity and aggregates at infinity are rejected. Public keys are checked
+// to be in the subgroup if the suite decodes them with SkipDecodeSubgroupCheck.
+func EthAggregatePublicKeys(publicKeys []PublicKey) (PublicKey, error) {
+	return currentSuite().EthAggregatePublicKeys(publicKeys)
+}
+
+// EthFastAggregateVerify verifies aggregate signature following eth_fast_aggregate_verify of the
+// consensus spec. Without public keys, only the signature at infinity is valid, which is the case
+// of a sync committee aggregate without participants. The signature at infinity is not decoded
+// with default validation options, so such aggregates are decoded with a suite with AllowInfinity.
+func EthFastAggregateVerify(signature Signature, publicKeys []PublicKey, message []byte) bool {
+	return currentSuite().EthFastAggregateVerify(signature, publicKeys, message)
+}
+
+func (suite *Suite) EthAggregatePublicKeys(publicKeys []PublicKey) (PublicKey, error) {
+	if len(publicKeys) == 0 {
+		return nil, errEmptyPublicKeys
+	}
+	_, infinite := suite.backend.Variant().publicKeyEncodings()
+	for _, publicKey := range publicKeys {
+		if publicKey == nil {
+			return nil, errInvalidPublicKey
+		}
+		if bytes.Equal(infinite, publicKey.ToBytes()) {
+			return nil, errInfinitePublicKey
+		}
+		// decoding the uncompressed encoding with the backend checks the subgroup without decompression
+		if suite.options.SkipDecodeSubgroupCheck {
+			if _, err := suite.backend.PublicKeyFromUncompressed(publicKey.ToUncompressed()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	aggregated, err := suite.backend.AggregatePublicKeys(publicKeys)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(infinite, aggregated.ToBytes()) {
+		return nil, errInfinitePublicKey
+	}
+	return aggregated, nil
+}
+
+func (suite *Suite) EthFastAggregateVerify(signature Signature, publicKeys []PublicKey, message []byte) bool {
+	if signature == nil || suite.ciphersuite != PoP {
+		return false
+	}
+	if len(publicKeys) == 0 {
+		_, infinite := suite.backend.Variant().signatureEncodings()
+		return bytes.Equal(infinite, signature.ToBytes())
+	}
+	return suite.backend.FastAggregateVerify(signature, publicKeys, message, suite.dst, ethValidationOptions)
+}
@@ -1,12 +1,14 @@
 module github.com/kilic/bls12cross/bls
 
-go 1.16
+go 1.18
 
 require (
 	github.com/herumi/bls-eth-go-binary v0.0.0-20210407105559-9588dcfc7de7
 	github.com/kilic/bls12-381 v0.1.1-0.20210208205449-6045b0235e36
-	github.com/supranational/blst v0.3.4
-	golang.org/x/sys v0.0.0-20210326220804-49726bf1d181 // indirect
-
+	github.com/supranational/blst v0.3.16
+	golang.org/x/crypto v0.24.0
+	golang.org/x/text v0.16.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
+require golang.org/x/sys v0.21.0 // indirect
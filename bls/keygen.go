@@ -0,0 +1,16 @@
+package cross_bls
+
+// KeyGen deterministically derives a secret key from ikm and optional keyInfo
+// as described in KeyGen of draft-irtf-cfrg-bls-signature.
+// Derived keys are independent of the backend, so the same ikm yields the same key on every backend.
+// ikm must be at least 32 bytes and should be secret and uniformly random.
+func KeyGen(ikm, keyInfo []byte) (SecretKey, error) {
+	return currentSuite().KeyGen(ikm, keyInfo)
+}
+
+func (suite *Suite) KeyGen(ikm, keyInfo []byte) (SecretKey, error) {
+	if len(ikm) < 32 {
+		return nil, errShortSeed
+	}
+	return suite.secretKeyFromBig(hkdfModR(ikm, keyInfo))
+}
@@ -0,0 +1,339 @@
+// Package keystore stores BLS secret keys in EIP-2335 encrypted keystores.
+package keystore
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	cross_bls "github.com/kilic/bls12cross/bls"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/text/unicode/norm"
+)
+
+const version = 4
+
+// dkLen is the length of derived keys, whose first half is the cipher key and second half
+// is the checksum key.
+const dkLen = 32
+
+// Bounds of key derivation parameters of loaded keystores, which are checked before deriving
+// keys so that untrusted keystores cannot exhaust memory or time. Scrypt uses 128 * n * r bytes
+// and runs n * r * p rounds, and the bound allows four times the parameters suggested in EIP-2335.
+const (
+	maxScryptCost = 1 << 23
+	maxPBKDF2C    = 1 << 20
+)
+
+// Key derivation functions supported in keystores.
+const (
+	KDFScrypt = "scrypt"
+	KDFPBKDF2 = "pbkdf2"
+)
+
+const (
+	checksumSHA256  = "sha256"
+	cipherAES128CTR = "aes-128-ctr"
+	prfHMACSHA256   = "hmac-sha256"
+)
+
+var (
+	errUnknownKDF        = errors.New("unknown key derivation function")
+	errUnknownChecksum   = errors.New("unknown checksum function")
+	errUnknownCipher     = errors.New("unknown cipher function")
+	errInvalidParams     = errors.New("invalid module params")
+	errInvalidChecksum   = errors.New("invalid checksum, wrong password")
+	errInvalidVersion    = errors.New("unsupported keystore version")
+	errPublicKeyMismatch = errors.New("public key does not match the secret key")
+)
+
+// Keystore is the EIP-2335 json representation of an encrypted secret key.
+type Keystore struct {
+	Crypto      Crypto `json:"crypto"`
+	Description string `json:"description"`
+	PublicKey   string `json:"pubkey"`
+	Path        string `json:"path"`
+	UUID        string `json:"uuid"`
+	Version     int    `json:"version"`
+}
+
+// Crypto holds key derivation, checksum and cipher modules of a keystore.
+type Crypto struct {
+	KDF      Module `json:"kdf"`
+	Checksum Module `json:"checksum"`
+	Cipher   Module `json:"cipher"`
+}
+
+// Module is a function with its parameters and message.
+type Module struct {
+	Function string          `json:"function"`
+	Params   json.RawMessage `json:"params"`
+	Message  string          `json:"message"`
+}
+
+type scryptParams struct {
+	DKLen int    `json:"dklen"`
+	N     int    `json:"n"`
+	P     int    `json:"p"`
+	R     int    `json:"r"`
+	Salt  string `json:"salt"`
+}
+
+type pbkdf2Params struct {
+	DKLen int    `json:"dklen"`
+	C     int    `json:"c"`
+	PRF   string `json:"prf"`
+	Salt  string `json:"salt"`
+}
+
+type cipherParams struct {
+	IV string `json:"iv"`
+}
+
+// Default key derivation parameters as suggested in EIP-2335.
+var (
+	defaultScryptParams = scryptParams{DKLen: dkLen, N: 262144, P: 1, R: 8}
+	defaultPBKDF2Params = pbkdf2Params{DKLen: dkLen, C: 262144, PRF: prfHMACSHA256}
+)
+
+// Load parses a json keystore.
+func Load(data []byte) (*Keystore, error) {
+	keystore := new(Keystore)
+	if err := json.Unmarshal(data, keystore); err != nil {
+		return nil, err
+	}
+	if keystore.Version != version {
+		return nil, errInvalidVersion
+	}
+	return keystore, nil
+}
+
+// Marshal returns json encoding of the keystore.
+func (keystore *Keystore) Marshal() ([]byte, error) {
+	return json.MarshalIndent(keystore, "", "  ")
+}
+
+// Encrypt encrypts the secret key with the password into a keystore
+// using the given key derivation function, either KDFScrypt or KDFPBKDF2.
+// Path is the EIP-2334 path the secret key is derived at, or empty if it is not derived.
+func Encrypt(secretKey cross_bls.SecretKey, password string, kdf string, path string) (*Keystore, error) {
+	if path != "" {
+		if _, err := cross_bls.ParsePath(path); err != nil {
+			return nil, err
+		}
+	}
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, err
+	}
+	var params interface{}
+	switch kdf {
+	case KDFScrypt:
+		p := defaultScryptParams
+		p.Salt = hex.EncodeToString(salt)
+		params = p
+	case KDFPBKDF2:
+		p := defaultPBKDF2Params
+		p.Salt = hex.EncodeToString(salt)
+		params = p
+	default:
+		return nil, errUnknownKDF
+	}
+	uuid, err := newUUID()
+	if err != nil {
+		return nil, err
+	}
+	return encrypt(secretKey, password, kdf, params, iv, path, uuid)
+}
+
+func encrypt(secretKey cross_bls.SecretKey, password string, kdf string, params interface{}, iv []byte, path, uuid string) (*Keystore, error) {
+	kdfParams, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	cipherParams, err := json.Marshal(cipherParams{hex.EncodeToString(iv)})
+	if err != nil {
+		return nil, err
+	}
+	keystore := &Keystore{
+		Crypto: Crypto{
+			KDF:      Module{kdf, kdfParams, ""},
+			Checksum: Module{checksumSHA256, json.RawMessage("{}"), ""},
+			Cipher:   Module{cipherAES128CTR, cipherParams, ""},
+		},
+		PublicKey: hex.EncodeToString(secretKey.PublicKey().ToBytes()),
+		Path:      path,
+		UUID:      uuid,
+		Version:   version,
+	}
+	decryptionKey, err := keystore.decryptionKey(password)
+	if err != nil {
+		return nil, err
+	}
+	cipherMessage, err := aes128CTR(decryptionKey[:16], iv, secretKey.ToBytes())
+	if err != nil {
+		return nil, err
+	}
+	keystore.Crypto.Cipher.Message = hex.EncodeToString(cipherMessage)
+	keystore.Crypto.Checksum.Message = hex.EncodeToString(checksum(decryptionKey, cipherMessage))
+	return keystore, nil
+}
+
+// Decrypt decrypts the secret key of the keystore with the password.
+// Secret key is created in the backend of suite, and the public key of the keystore
+// is checked in the variant of suite.
+func (keystore *Keystore) Decrypt(suite *cross_bls.Suite, password string) (cross_bls.SecretKey, error) {
+	secretKeyBytes, err := keystore.DecryptBytes(password)
+	if err != nil {
+		return nil, err
+	}
+	secretKey, err := suite.SecretKeyFromBytes(secretKeyBytes)
+	if err != nil {
+		return nil, err
+	}
+	if keystore.PublicKey != "" {
+		publicKey, err := hex.DecodeString(strings.TrimPrefix(keystore.PublicKey, "0x"))
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(publicKey, secretKey.PublicKey().ToBytes()) {
+			return nil, errPublicKeyMismatch
+		}
+	}
+	return secretKey, nil
+}
+
+// DecryptBytes decrypts the secret key of the keystore with the password
+// and returns it in big endian bytes.
+func (keystore *Keystore) DecryptBytes(password string) ([]byte, error) {
+	if keystore.Crypto.Checksum.Function != checksumSHA256 {
+		return nil, errUnknownChecksum
+	}
+	if keystore.Crypto.Cipher.Function != cipherAES128CTR {
+		return nil, errUnknownCipher
+	}
+	decryptionKey, err := keystore.decryptionKey(password)
+	if err != nil {
+		return nil, err
+	}
+	cipherMessage, err := hex.DecodeString(keystore.Crypto.Cipher.Message)
+	if err != nil {
+		return nil, err
+	}
+	expected, err := hex.DecodeString(keystore.Crypto.Checksum.Message)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(expected, checksum(decryptionKey, cipherMessage)) {
+		return nil, errInvalidChecksum
+	}
+	params := new(cipherParams)
+	if err := json.Unmarshal(keystore.Crypto.Cipher.Params, params); err != nil {
+		return nil, err
+	}
+	iv, err := hex.DecodeString(params.IV)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != aes.BlockSize {
+		return nil, errInvalidParams
+	}
+	return aes128CTR(decryptionKey[:16], iv, cipherMessage)
+}
+
+// decryptionKey derives the decryption key from password with the key derivation module.
+func (keystore *Keystore) decryptionKey(password string) ([]byte, error) {
+	_password := normalizePassword(password)
+	kdf := keystore.Crypto.KDF
+	switch kdf.Function {
+	case KDFScrypt:
+		params := new(scryptParams)
+		if err := json.Unmarshal(kdf.Params, params); err != nil {
+			return nil, err
+		}
+		salt, err := hex.DecodeString(params.Salt)
+		if err != nil {
+			return nil, err
+		}
+		if !validScryptParams(params) {
+			return nil, errInvalidParams
+		}
+		return scrypt.Key(_password, salt, params.N, params.R, params.P, params.DKLen)
+	case KDFPBKDF2:
+		params := new(pbkdf2Params)
+		if err := json.Unmarshal(kdf.Params, params); err != nil {
+			return nil, err
+		}
+		salt, err := hex.DecodeString(params.Salt)
+		if err != nil {
+			return nil, err
+		}
+		if params.DKLen != dkLen || params.C <= 0 || params.C > maxPBKDF2C || params.PRF != prfHMACSHA256 {
+			return nil, errInvalidParams
+		}
+		return pbkdf2.Key(_password, salt, params.C, params.DKLen, sha256.New), nil
+	}
+	return nil, errUnknownKDF
+}
+
+// validScryptParams returns true if n is a power of two greater than one, r and p are positive
+// and the cost of derivation is bounded.
+func validScryptParams(params *scryptParams) bool {
+	if params.DKLen != dkLen || params.N <= 1 || params.N&(params.N-1) != 0 || params.R <= 0 || params.P <= 0 {
+		return false
+	}
+	// checked one factor at a time so that the product does not overflow
+	return params.N <= maxScryptCost && params.R <= maxScryptCost/params.N && params.P <= maxScryptCost/(params.N*params.R)
+}
+
+// normalizePassword applies NFKD normalization and strips control codes from password.
+func normalizePassword(password string) []byte {
+	normalized := norm.NFKD.String(password)
+	out := make([]byte, 0, len(normalized))
+	for _, r := range normalized {
+		// C0, C1 and Delete control codes
+		if r <= 0x1f || (r >= 0x7f && r <= 0x9f) {
+			continue
+		}
+		out = append(out, string(r)...)
+	}
+	return out
+}
+
+func checksum(decryptionKey, cipherMessage []byte) []byte {
+	h := sha256.Sum256(append(append([]byte{}, decryptionKey[16:32]...), cipherMessage...))
+	return h[:]
+}
+
+func aes128CTR(key, iv, in []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(in))
+	cipher.NewCTR(block, iv).XORKeyStream(out, in)
+	return out, nil
+}
+
+// newUUID returns a random version 4 uuid.
+func newUUID() (string, error) {
+	u := make([]byte, 16)
+	if _, err := rand.Read(u); err != nil {
+		return "", err
+	}
+	u[6] = (u[6] & 0x0f) | 0x40
+	u[8] = (u[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:]), nil
+}
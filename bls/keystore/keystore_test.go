@@ -0,0 +1,186 @@
+package keystore
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"testing"
+
+	cross_bls "github.com/kilic/bls12cross/bls"
+)
+
+var library = flag.String("lib", "none", "select a library")
+
+// newSuite returns the proof of possession suite of the backend selected with -lib, or of herumi.
+func newSuite(t *testing.T) *cross_bls.Suite {
+	name := *library
+	if name == "none" {
+		name = "herumi"
+	}
+	backend, err := cross_bls.Lookup(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	suite, err := cross_bls.NewCiphersuite(backend, cross_bls.PoP, cross_bls.ValidationOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return suite
+}
+
+// test vectors of https://eips.ethereum.org/EIPS/eip-2335#test-cases
+const testPassword = "\U0001d531\U0001d522\U0001d530\U0001d531\U0001d52d\U0001d51e\U0001d530\U0001d530\U0001d534\U0001d52c\U0001d52f\U0001d521\U0001f511"
+const testSecretKey = "000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f"
+
+func loadTestKeystore(t *testing.T, name string) *Keystore {
+	data, err := ioutil.ReadFile("./test_vectors/" + name + ".json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keystore, err := Load(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keystore
+}
+
+func TestDecrypt(t *testing.T) {
+	suite := newSuite(t)
+	expected, _ := hex.DecodeString(testSecretKey)
+	for _, name := range []string{KDFScrypt, KDFPBKDF2} {
+		keystore := loadTestKeystore(t, name)
+		secretKey, err := keystore.Decrypt(suite, testPassword)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !bytes.Equal(expected, secretKey.ToBytes()) {
+			t.Fatalf("%s: secret key", name)
+		}
+		if _, err := keystore.Decrypt(suite, "testpassword"); err != errInvalidChecksum {
+			t.Fatalf("%s: wrong password must be rejected", name)
+		}
+	}
+}
+
+func TestEncrypt(t *testing.T) {
+	secretKey, err := newSuite(t).SecretKeyFromBytes(mustDecode(t, testSecretKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// encryption with parameters of test vectors results the same keystore messages
+	for _, name := range []string{KDFScrypt, KDFPBKDF2} {
+		expected := loadTestKeystore(t, name)
+		var params interface{}
+		if name == KDFScrypt {
+			params = new(scryptParams)
+		} else {
+			params = new(pbkdf2Params)
+		}
+		if err := json.Unmarshal(expected.Crypto.KDF.Params, params); err != nil {
+			t.Fatal(err)
+		}
+		iv := mustDecode(t, "264daa3f303d7259501c93d997d84fe6")
+		keystore, err := encrypt(secretKey, testPassword, name, params, iv, expected.Path, expected.UUID)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if keystore.Crypto.Cipher.Message != expected.Crypto.Cipher.Message {
+			t.Fatalf("%s: cipher message", name)
+		}
+		if keystore.Crypto.Checksum.Message != expected.Crypto.Checksum.Message {
+			t.Fatalf("%s: checksum message", name)
+		}
+		if keystore.PublicKey != expected.PublicKey {
+			t.Fatalf("%s: public key", name)
+		}
+		if keystore.Path != expected.Path {
+			t.Fatalf("%s: path", name)
+		}
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping key derivation in short mode")
+	}
+	suite := newSuite(t)
+	secretKey := suite.RandSecretKey()
+	path := "m/12381/3600/0/0/0"
+	password := "test\u0000passwordé"
+	for _, kdf := range []string{KDFScrypt, KDFPBKDF2} {
+		keystore, err := Encrypt(secretKey, password, kdf, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := keystore.Marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		keystore, err = Load(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// control codes are stripped and password is NFKD normalized
+		decrypted, err := keystore.Decrypt(suite, "testpasswordé")
+		if err != nil {
+			t.Fatalf("%s: %v", kdf, err)
+		}
+		if !decrypted.Equal(secretKey) {
+			t.Fatalf("%s: secret key", kdf)
+		}
+		if keystore.Path != path {
+			t.Fatalf("%s: path", kdf)
+		}
+	}
+	if _, err := Encrypt(secretKey, password, "argon2", ""); err != errUnknownKDF {
+		t.Fatalf("unknown kdf")
+	}
+	if _, err := Encrypt(secretKey, password, KDFPBKDF2, "m/x"); err == nil {
+		t.Fatalf("invalid path")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	if _, err := Load([]byte(`{"version": 3}`)); err != errInvalidVersion {
+		t.Fatalf("version must be checked")
+	}
+	suite := newSuite(t)
+	keystore := loadTestKeystore(t, KDFPBKDF2)
+	keystore.PublicKey = hex.EncodeToString(suite.RandSecretKey().PublicKey().ToBytes())
+	if _, err := keystore.Decrypt(suite, testPassword); err != errPublicKeyMismatch {
+		t.Fatalf("public key must be checked")
+	}
+}
+
+func TestKDFParams(t *testing.T) {
+	for _, params := range []struct {
+		kdf  string
+		json string
+	}{
+		{KDFScrypt, `{"dklen": 64, "n": 262144, "p": 1, "r": 8, "salt": ""}`},
+		{KDFScrypt, `{"dklen": 32, "n": 262143, "p": 1, "r": 8, "salt": ""}`},
+		{KDFScrypt, `{"dklen": 32, "n": 1, "p": 1, "r": 8, "salt": ""}`},
+		{KDFScrypt, `{"dklen": 32, "n": 1073741824, "p": 1, "r": 8, "salt": ""}`},
+		{KDFScrypt, `{"dklen": 32, "n": 262144, "p": 1, "r": 1073741824, "salt": ""}`},
+		{KDFScrypt, `{"dklen": 32, "n": 262144, "p": 1073741824, "r": 8, "salt": ""}`},
+		{KDFScrypt, `{"dklen": 32, "n": 262144, "p": 0, "r": 8, "salt": ""}`},
+		{KDFPBKDF2, `{"dklen": 64, "c": 262144, "prf": "hmac-sha256", "salt": ""}`},
+		{KDFPBKDF2, `{"dklen": 32, "c": 1073741824, "prf": "hmac-sha256", "salt": ""}`},
+	} {
+		keystore := loadTestKeystore(t, params.kdf)
+		keystore.Crypto.KDF.Params = json.RawMessage(params.json)
+		if _, err := keystore.DecryptBytes(testPassword); err != errInvalidParams {
+			t.Fatalf("%s params must be rejected: %s", params.kdf, params.json)
+		}
+	}
+}
+
+func mustDecode(t *testing.T, s string) []byte {
+	out, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
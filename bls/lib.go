@@ -4,6 +4,8 @@ import (
 	"encoding/hex"
 	"errors"
 	"runtime"
+	"sync"
+	"sync/atomic"
 
 	herumi "github.com/herumi/bls-eth-go-binary/bls"
 	kilic "github.com/kilic/bls12-381"
@@ -11,28 +13,51 @@ import (
 )
 
 var (
-	errZeroSecretKey     = errors.New("zero secret key")
-	errZeroPublicKey     = errors.New("zero public key")
-	errInfinitePublicKey = errors.New("infinite public key")
-	errInvalidPublicKey  = errors.New("invalid public key")
-	errZeroSignature     = errors.New("zero signature")
-	errInfiniteSignature = errors.New("infinite signature")
-	errInvalidSignature  = errors.New("invalid signature")
-	errSecretKeySize     = errors.New("invalid secret key size")
-	errInvalidSecretKey  = errors.New("invalid secret key")
-	errPublicKeySize     = errors.New("invalid public key size")
-	errSignatureSize     = errors.New("invalid signature size")
+	errInvalidPublicKey    = errors.New("invalid public key")
+	errInvalidSignature    = errors.New("invalid signature")
+	errInvalidSecretKey    = errors.New("invalid secret key")
+	errUnconvertible       = errors.New("value cannot be converted")
+	errUnaggregatable      = errors.New("value cannot be aggregated")
+	errEmptySignatureSets  = errors.New("no signature sets")
+	errInvalidSignatureSet = errors.New("invalid signature set")
+	errInvalidThreshold    = errors.New("invalid threshold")
+	errInvalidShareIndex   = errors.New("invalid share index")
+	errNoShares            = errors.New("no shares")
+	errNotEnoughShares     = errors.New("not enough valid shares")
+	errBitfieldSize        = errors.New("bitfield does not match committee size")
+	errBitfieldOverlap     = errors.New("bitfields overlap")
+	errEmptyPublicKeys     = errors.New("no public keys")
+	errUnsupported         = errors.New("operation is not supported by backend")
 )
 
 const (
 	libHerumi = "herumi"
 	libBLST   = "blst"
 	libKilic  = "kilic"
+
+	libHerumiMinSig = "herumi-minsig"
+	libBLSTMinSig   = "blst-minsig"
+	libKilicMinSig  = "kilic-minsig"
 )
 
 var library = libHerumi
 
+// defaultSuite holds the *Suite of package level functions. UseBackend replaces it while
+// package level functions load it, so they are safe to be used concurrently.
+var defaultSuite atomic.Value
+
+// currentSuite returns the suite of package level functions.
+func currentSuite() *Suite {
+	return defaultSuite.Load().(*Suite)
+}
+
 func init() {
+	Register(herumiBackend{})
+	Register(blstBackend{})
+	Register(kilicBackend{})
+	Register(herumiMinSigBackend{})
+	Register(blstMinSigBackend{})
+	Register(kilicMinSigBackend{})
 	_init()
 }
 
@@ -49,38 +74,28 @@ func _init() {
 		panic("cannot set infinite signature")
 	}
 
-	switch library {
-	case libHerumi:
-		initHerumi()
-	case libBLST:
-		initBLST()
-	case libKilic:
-		initKilic()
-	}
+	_ = UseBackend(library)
 }
 
 func UseHerumi() {
-	library = libHerumi
-	initHerumi()
+	_ = UseBackend(libHerumi)
 }
 
 func UseBLST() {
-	library = libBLST
-	initBLST()
+	_ = UseBackend(libBLST)
 }
 
 func UseKilic() {
-	library = libKilic
-	initKilic()
+	_ = UseBackend(libKilic)
 }
 
 var blstSingleProc = false
-var blstInitialized = false
-var kilicInitialized = false
-var herumiInitialized = false
+var blstOnce sync.Once
+var kilicOnce sync.Once
+var herumiOnce sync.Once
 
 func initBLST() {
-	if !blstInitialized {
+	blstOnce.Do(func() {
 		if blstSingleProc {
 			blst.SetMaxProcs(1)
 		} else {
@@ -90,27 +105,22 @@ func initBLST() {
 			}
 			blst.SetMaxProcs(maxProcs)
 		}
-		blstInitialized = true
-	}
+	})
 }
 
 func initHerumi() {
-	if !herumiInitialized {
+	herumiOnce.Do(func() {
 		if err := herumi.Init(herumi.BLS12_381); err != nil {
 			panic(err)
 		}
 		if err := herumi.SetETHmode(herumi.EthModeDraft07); err != nil {
 			panic(err)
 		}
-		herumi.VerifyPublicKeyOrder(true)
-		herumi.VerifySignatureOrder(true)
-		herumiInitialized = true
-	}
+	})
 }
 
 func initKilic() {
-	if !kilicInitialized {
+	kilicOnce.Do(func() {
 		kilicGroupOrder = new(kilic.Fr).FromBytes(kilic.NewG1().Q().Bytes())
-		kilicInitialized = true
-	}
+	})
 }
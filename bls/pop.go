@@ -0,0 +1,34 @@
+package cross_bls
+
+// popDST returns the domain separation tag of proofs of possession for the variant.
+func popDST(variant Variant) []byte {
+	return []byte("BLS_POP_BLS12381" + variant.hashGroup() + "_XMD:SHA-256_SSWU_RO_POP_")
+}
+
+// popValidationOptions are always applied in proof of possession verification,
+// as public keys with a valid proof are trusted in fast aggregate verification.
+var popValidationOptions = ValidationOptions{CheckSignatureSubgroup: true, ValidatePublicKey: true}
+
+// PopProve returns the proof of possession of the secret key.
+func PopProve(secretKey SecretKey) Signature {
+	return currentSuite().PopProve(secretKey)
+}
+
+// PopVerify checks that proof is a valid proof of possession for the public key.
+func PopVerify(publicKey PublicKey, proof Signature) bool {
+	return currentSuite().PopVerify(publicKey, proof)
+}
+
+func (suite *Suite) PopProve(secretKey SecretKey) Signature {
+	if secretKey == nil {
+		return nil
+	}
+	return suite.backend.Sign(secretKey, secretKey.PublicKey().ToBytes(), popDST(suite.backend.Variant()))
+}
+
+func (suite *Suite) PopVerify(publicKey PublicKey, proof Signature) bool {
+	if publicKey == nil || proof == nil {
+		return false
+	}
+	return suite.backend.Verify(proof, publicKey, publicKey.ToBytes(), popDST(suite.backend.Variant()), popValidationOptions)
+}
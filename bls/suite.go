@@ -0,0 +1,231 @@
+package cross_bls
+
+import (
+	"bytes"
+)
+
+// ValidationOptions selects checks applied to signatures and public keys in decoding and verification.
+// Zero value decodes only public keys and signatures in the prime order subgroup other than infinity,
+// and applies no further checks in verification.
+type ValidationOptions struct {
+	// CheckSignatureSubgroup rejects signatures that are not in the prime order subgroup in verification.
+	CheckSignatureSubgroup bool
+	// ValidatePublicKey rejects public keys that are infinity or not in the prime order subgroup in verification.
+	ValidatePublicKey bool
+	// SkipDecodeSubgroupCheck decodes public keys and signatures without checking the subgroup,
+	// which is faster but leaves the check to verification with CheckSignatureSubgroup and ValidatePublicKey.
+	// kilic backends only skip the check for uncompressed encodings, as kilic decompression always checks it.
+	SkipDecodeSubgroupCheck bool
+	// AllowInfinity decodes the point at infinity as a public key or a signature.
+	AllowInfinity bool
+	// AllowZero decodes all zero encodings as the point at infinity, which is still rejected
+	// unless AllowInfinity is set.
+	AllowZero bool
+}
+
+var defaultValidationOptions = ValidationOptions{}
+
+// Suite is an instance of BLS signature scheme bound to a backend, a domain separation tag
+// and validation options. Suites are safe to be used concurrently with each other.
+type Suite struct {
+	backend     Backend
+	dst         []byte
+	options     ValidationOptions
+	ciphersuite Ciphersuite
+}
+
+// NewSuite returns a suite which runs operations with given backend, domain separation tag and options.
+// Messages are processed following proof of possession ciphersuite rules.
+func NewSuite(backend Backend, dst []byte, options ValidationOptions) *Suite {
+	initBackend(backend)
+	_dst := make([]byte, len(dst))
+	copy(_dst, dst)
+	return &Suite{backend, _dst, options, PoP}
+}
+
+// Backend returns the backend of the suite.
+func (suite *Suite) Backend() Backend {
+	return suite.backend
+}
+
+// DST returns domain separation tag of the suite.
+func (suite *Suite) DST() []byte {
+	return append([]byte{}, suite.dst...)
+}
+
+// Options returns validation options of the suite.
+func (suite *Suite) Options() ValidationOptions {
+	return suite.options
+}
+
+// Ciphersuite returns the ciphersuite which rules are followed by the suite.
+func (suite *Suite) Ciphersuite() Ciphersuite {
+	return suite.ciphersuite
+}
+
+func (suite *Suite) RandSecretKey() SecretKey {
+	return suite.backend.RandSecretKey()
+}
+
+func (suite *Suite) SecretKeyFromBytes(_secretKey []byte) (SecretKey, error) {
+	if len(_secretKey) != SecretKeySize {
+		return nil, errSecretKeySize
+	}
+	if bytes.Equal(zeroSecretKey, _secretKey) {
+		return nil, errZeroSecretKey
+	}
+	secretKey, err := suite.backend.SecretKeyFromBytes(_secretKey)
+	if err != nil {
+		return nil, err
+	}
+	return secretKey, nil
+}
+
+func (suite *Suite) PublicKeyFromBytes(compressed []byte) (PublicKey, error) {
+	variant := suite.backend.Variant()
+	if len(compressed) != variant.PublicKeySize() {
+		return nil, errPublicKeySize
+	}
+	zero, infinite := variant.publicKeyEncodings()
+	infinity, err := suite.checkInfinity(bytes.Equal(zero, compressed), bytes.Equal(infinite, compressed), errZeroPublicKey, errInfinitePublicKey)
+	if err != nil {
+		return nil, err
+	}
+	if infinity {
+		return suite.backend.NewPublicKeyAggregator().Result(), nil
+	}
+	if decoder, ok := suite.backend.(uncheckedDecoder); ok && suite.options.SkipDecodeSubgroupCheck {
+		return decoder.publicKeyFromBytesUnchecked(compressed)
+	}
+	return suite.backend.PublicKeyFromBytes(compressed)
+}
+
+func (suite *Suite) SignatureFromBytes(compressed []byte) (Signature, error) {
+	variant := suite.backend.Variant()
+	if len(compressed) != variant.SignatureSize() {
+		return nil, errSignatureSize
+	}
+	zero, infinite := variant.signatureEncodings()
+	infinity, err := suite.checkInfinity(bytes.Equal(zero, compressed), bytes.Equal(infinite, compressed), errZeroSignature, errInfiniteSignature)
+	if err != nil {
+		return nil, err
+	}
+	if infinity {
+		return suite.backend.NewSignatureAggregator().Result(), nil
+	}
+	if decoder, ok := suite.backend.(uncheckedDecoder); ok && suite.options.SkipDecodeSubgroupCheck {
+		return decoder.signatureFromBytesUnchecked(compressed)
+	}
+	return suite.backend.SignatureFromBytes(compressed)
+}
+
+func (suite *Suite) PublicKeyFromUncompressed(uncompressed []byte) (PublicKey, error) {
+	size := suite.backend.Variant().UncompressedPublicKeySize()
+	if len(uncompressed) == size {
+		infinity, err := suite.checkInfinity(isZeroBytes(uncompressed), bytes.Equal(infiniteUncompressed(size), uncompressed), errZeroPublicKey, errInfinitePublicKey)
+		if err != nil {
+			return nil, err
+		}
+		if infinity {
+			return suite.backend.NewPublicKeyAggregator().Result(), nil
+		}
+	}
+	if err := checkUncompressedPublicKey(uncompressed, size); err != nil {
+		return nil, err
+	}
+	if decoder, ok := suite.backend.(uncheckedDecoder); ok && suite.options.SkipDecodeSubgroupCheck {
+		return decoder.publicKeyFromBytesUnchecked(uncompressed)
+	}
+	return suite.backend.PublicKeyFromUncompressed(uncompressed)
+}
+
+func (suite *Suite) SignatureFromUncompressed(uncompressed []byte) (Signature, error) {
+	size := suite.backend.Variant().UncompressedSignatureSize()
+	if len(uncompressed) == size {
+		infinity, err := suite.checkInfinity(isZeroBytes(uncompressed), bytes.Equal(infiniteUncompressed(size), uncompressed), errZeroSignature, errInfiniteSignature)
+		if err != nil {
+			return nil, err
+		}
+		if infinity {
+			return suite.backend.NewSignatureAggregator().Result(), nil
+		}
+	}
+	if err := checkUncompressedSignature(uncompressed, size); err != nil {
+		return nil, err
+	}
+	if decoder, ok := suite.backend.(uncheckedDecoder); ok && suite.options.SkipDecodeSubgroupCheck {
+		return decoder.signatureFromBytesUnchecked(uncompressed)
+	}
+	return suite.backend.SignatureFromUncompressed(uncompressed)
+}
+
+// checkInfinity applies AllowZero and AllowInfinity options to an encoding, and returns true
+// if the encoding is to be decoded as the point at infinity.
+func (suite *Suite) checkInfinity(zero, infinite bool, errZero, errInfinite error) (bool, error) {
+	if zero && !suite.options.AllowZero {
+		return false, errZero
+	}
+	if (zero || infinite) && !suite.options.AllowInfinity {
+		return false, errInfinite
+	}
+	return zero || infinite, nil
+}
+
+func (suite *Suite) AggregatePublicKeys(publicKeys []PublicKey) (PublicKey, error) {
+	return suite.backend.AggregatePublicKeys(publicKeys)
+}
+
+func (suite *Suite) AggregateSignatures(signatures []Signature) (Signature, error) {
+	return suite.backend.AggregateSignatures(signatures)
+}
+
+func (suite *Suite) Sign(secretKey SecretKey, message []byte) Signature {
+	if suite.ciphersuite == Aug {
+		if secretKey == nil {
+			return nil
+		}
+		message = augment(secretKey.PublicKey(), message)
+	}
+	return suite.backend.Sign(secretKey, message, suite.dst)
+}
+
+func (suite *Suite) Verify(signature Signature, publicKey PublicKey, message []byte) bool {
+	if suite.ciphersuite == Aug {
+		if publicKey == nil {
+			return false
+		}
+		message = augment(publicKey, message)
+	}
+	return suite.backend.Verify(signature, publicKey, message, suite.dst, suite.options)
+}
+
+// FastAggregateVerify is only defined in proof of possession ciphersuite.
+// Suites of other ciphersuites return false.
+func (suite *Suite) FastAggregateVerify(signature Signature, publicKeys []PublicKey, message []byte) bool {
+	if suite.ciphersuite != PoP {
+		return false
+	}
+	return suite.backend.FastAggregateVerify(signature, publicKeys, message, suite.dst, suite.options)
+}
+
+func (suite *Suite) AggregateVerify(signature Signature, publicKeys []PublicKey, messages [][]byte) bool {
+	switch suite.ciphersuite {
+	case Basic:
+		if !distinct(messages) {
+			return false
+		}
+	case Aug:
+		if len(messages) != len(publicKeys) {
+			return false
+		}
+		_messages := make([][]byte, len(messages))
+		for i := 0; i < len(messages); i++ {
+			if publicKeys[i] == nil {
+				return false
+			}
+			_messages[i] = augment(publicKeys[i], messages[i])
+		}
+		messages = _messages
+	}
+	return suite.backend.AggregateVerify(signature, publicKeys, messages, suite.dst, suite.options)
+}
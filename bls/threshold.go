@@ -0,0 +1,249 @@
+package cross_bls
+
+import (
+	"crypto/rand"
+	"math/big"
+	"sort"
+)
+
+// SplitSecretKey splits secret key into n shares where any t of them recover the secret key.
+// Shares are indexed from 1 to n and are evaluations of a random polynomial of degree t - 1
+// which constant term is the secret key.
+func SplitSecretKey(secretKey SecretKey, t, n int) (map[uint32]SecretKey, error) {
+	return currentSuite().SplitSecretKey(secretKey, t, n)
+}
+
+// RecoverSignature interpolates partial signatures of at least threshold many indexed key shares
+// into the signature of the secret key.
+func RecoverSignature(partials map[uint32]Signature) (Signature, error) {
+	return currentSuite().RecoverSignature(partials)
+}
+
+// RecoverPublicKey interpolates public keys of at least threshold many indexed key shares
+// into the public key of the secret key.
+func RecoverPublicKey(publicKeys map[uint32]PublicKey) (PublicKey, error) {
+	return currentSuite().RecoverPublicKey(publicKeys)
+}
+
+// CombineSignatures verifies partial signatures on message against public key shares of signers
+// and recovers the signature from threshold many valid partials with the smallest indices.
+// Partials are batch verified first and checked one by one only if the batch fails.
+// Indices of invalid partials are returned in ascending order, also when there are not
+// enough valid partials to recover the signature.
+func CombineSignatures(partials map[uint32]Signature, publicKeyShares map[uint32]PublicKey, message []byte, threshold int) (Signature, []uint32, error) {
+	return currentSuite().CombineSignatures(partials, publicKeyShares, message, threshold)
+}
+
+func (suite *Suite) SplitSecretKey(secretKey SecretKey, t, n int) (map[uint32]SecretKey, error) {
+	if t < 1 || n < t || uint64(n) > uint64(^uint32(0)) {
+		return nil, errInvalidThreshold
+	}
+	if secretKey == nil {
+		return nil, errInvalidSecretKey
+	}
+	coefficients := make([]*big.Int, t)
+	coefficients[0] = new(big.Int).SetBytes(secretKey.ToBytes())
+	for i := 1; i < t; i++ {
+		coefficient, err := rand.Int(rand.Reader, groupOrder)
+		if err != nil {
+			return nil, err
+		}
+		coefficients[i] = coefficient
+	}
+	shares := make(map[uint32]SecretKey, n)
+	for i := 1; i <= n; i++ {
+		share, err := suite.secretKeyFromBig(EvaluatePolynomial(coefficients, uint32(i)))
+		if err != nil {
+			return nil, err
+		}
+		shares[uint32(i)] = share
+	}
+	return shares, nil
+}
+
+func (suite *Suite) RecoverSignature(partials map[uint32]Signature) (Signature, error) {
+	multiplier, ok := suite.backend.(pointMultiplier)
+	if !ok {
+		return nil, errUnsupported
+	}
+	indices := make([]uint32, 0, len(partials))
+	for index, partial := range partials {
+		if partial == nil {
+			return nil, errInvalidSignature
+		}
+		indices = append(indices, index)
+	}
+	coefficients, err := lagrangeCoefficients(indices)
+	if err != nil {
+		return nil, err
+	}
+	terms := make([]Signature, len(indices))
+	for i, index := range indices {
+		if terms[i], err = multiplier.mulSignature(partials[index], coefficients[i]); err != nil {
+			return nil, err
+		}
+	}
+	signature, err := suite.backend.AggregateSignatures(terms)
+	if err != nil {
+		return nil, err
+	}
+	return suite.SignatureFromBytes(signature.ToBytes())
+}
+
+func (suite *Suite) RecoverPublicKey(publicKeys map[uint32]PublicKey) (PublicKey, error) {
+	multiplier, ok := suite.backend.(pointMultiplier)
+	if !ok {
+		return nil, errUnsupported
+	}
+	indices := make([]uint32, 0, len(publicKeys))
+	for index, publicKey := range publicKeys {
+		if publicKey == nil {
+			return nil, errInvalidPublicKey
+		}
+		indices = append(indices, index)
+	}
+	coefficients, err := lagrangeCoefficients(indices)
+	if err != nil {
+		return nil, err
+	}
+	terms := make([]PublicKey, len(indices))
+	for i, index := range indices {
+		if terms[i], err = multiplier.mulPublicKey(publicKeys[index], coefficients[i]); err != nil {
+			return nil, err
+		}
+	}
+	publicKey, err := suite.backend.AggregatePublicKeys(terms)
+	if err != nil {
+		return nil, err
+	}
+	return suite.PublicKeyFromBytes(publicKey.ToBytes())
+}
+
+func (suite *Suite) CombineSignatures(partials map[uint32]Signature, publicKeyShares map[uint32]PublicKey, message []byte, threshold int) (Signature, []uint32, error) {
+	if threshold < 1 {
+		return nil, nil, errInvalidThreshold
+	}
+	indices := make([]uint32, 0, len(partials))
+	invalid := []uint32{}
+	sets := []SignatureSet{}
+	for index, partial := range partials {
+		publicKey := publicKeyShares[index]
+		if index == 0 || partial == nil || publicKey == nil {
+			invalid = append(invalid, index)
+			continue
+		}
+		indices = append(indices, index)
+	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+	for _, index := range indices {
+		sets = append(sets, SignatureSet{publicKeyShares[index], message, partials[index]})
+	}
+	valid := indices
+	if len(sets) > 0 {
+		if ok, err := suite.VerifyMultipleSignatures(sets); err != nil || !ok {
+			valid = []uint32{}
+			for i, set := range sets {
+				if suite.Verify(set.Signature, set.PublicKey, set.Message) {
+					valid = append(valid, indices[i])
+				} else {
+					invalid = append(invalid, indices[i])
+				}
+			}
+		}
+	}
+	sort.Slice(invalid, func(i, j int) bool { return invalid[i] < invalid[j] })
+	if len(valid) < threshold {
+		return nil, invalid, errNotEnoughShares
+	}
+	_partials := make(map[uint32]Signature, threshold)
+	for _, index := range valid[:threshold] {
+		_partials[index] = partials[index]
+	}
+	signature, err := suite.RecoverSignature(_partials)
+	if err != nil {
+		return nil, invalid, err
+	}
+	return signature, invalid, nil
+}
+
+// MulPublicKey returns public key multiplied by scalar modulo group order. The product is not
+// decoded with validation options of the suite, so it may be the point at infinity.
+func (suite *Suite) MulPublicKey(publicKey PublicKey, scalar *big.Int) (PublicKey, error) {
+	multiplier, ok := suite.backend.(pointMultiplier)
+	if !ok {
+		return nil, errUnsupported
+	}
+	if publicKey == nil {
+		return nil, errInvalidPublicKey
+	}
+	return multiplier.mulPublicKey(publicKey, scalar)
+}
+
+// EvaluateCommitments returns C_0 + C_1 * x + ... + C_(t-1) * x^(t-1) where C_i are public keys
+// of coefficients of a polynomial, which is the public key of the share at index x.
+func (suite *Suite) EvaluateCommitments(commitments []PublicKey, x uint32) (PublicKey, error) {
+	if len(commitments) == 0 {
+		return nil, errInvalidThreshold
+	}
+	_x := new(big.Int).SetUint64(uint64(x))
+	power := big.NewInt(1)
+	terms := make([]PublicKey, len(commitments))
+	for i, commitment := range commitments {
+		term, err := suite.MulPublicKey(commitment, power)
+		if err != nil {
+			return nil, err
+		}
+		terms[i] = term
+		power.Mul(power, _x).Mod(power, groupOrder)
+	}
+	publicKey, err := suite.backend.AggregatePublicKeys(terms)
+	if err != nil {
+		return nil, err
+	}
+	return suite.PublicKeyFromBytes(publicKey.ToBytes())
+}
+
+// GroupOrder returns the order of G1 and G2 subgroups, which is the modulus of secret keys
+// and of coefficients of secret sharing polynomials.
+func GroupOrder() *big.Int {
+	return new(big.Int).Set(groupOrder)
+}
+
+// EvaluatePolynomial returns f(x) = c_0 + c_1 * x + ... + c_(t-1) * x^(t-1) modulo group order.
+func EvaluatePolynomial(coefficients []*big.Int, x uint32) *big.Int {
+	_x := new(big.Int).SetUint64(uint64(x))
+	y := new(big.Int)
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		y.Mul(y, _x).Add(y, coefficients[i]).Mod(y, groupOrder)
+	}
+	return y
+}
+
+// lagrangeCoefficients returns coefficients that interpolate the polynomial at zero
+// from its evaluations at given indices.
+func lagrangeCoefficients(indices []uint32) ([]*big.Int, error) {
+	if len(indices) == 0 {
+		return nil, errNoShares
+	}
+	coefficients := make([]*big.Int, len(indices))
+	for i := 0; i < len(indices); i++ {
+		if indices[i] == 0 {
+			return nil, errInvalidShareIndex
+		}
+		xi := new(big.Int).SetUint64(uint64(indices[i]))
+		numerator, denominator := big.NewInt(1), big.NewInt(1)
+		for j := 0; j < len(indices); j++ {
+			if i == j {
+				continue
+			}
+			xj := new(big.Int).SetUint64(uint64(indices[j]))
+			numerator.Mul(numerator, xj).Mod(numerator, groupOrder)
+			denominator.Mul(denominator, new(big.Int).Sub(xj, xi)).Mod(denominator, groupOrder)
+		}
+		if denominator.ModInverse(denominator, groupOrder) == nil {
+			return nil, errInvalidShareIndex
+		}
+		coefficients[i] = numerator.Mul(numerator, denominator).Mod(numerator, groupOrder)
+	}
+	return coefficients, nil
+}
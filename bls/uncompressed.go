@@ -0,0 +1,55 @@
+package cross_bls
+
+// Uncompressed points are encoded as x and y coordinates with all flag bits unset.
+// Point at infinity is rejected as a public key or a signature, so that every
+// backend applies the same rules as the compressed decoders.
+
+const uncompressedFlagMask = 0xe0
+
+// infiniteUncompressed returns uncompressed encoding of point at infinity with infinity flag.
+func infiniteUncompressed(size int) []byte {
+	out := make([]byte, size)
+	out[0] = 0x40
+	return out
+}
+
+func checkUncompressedPublicKey(uncompressed []byte, size int) error {
+	if len(uncompressed) != size {
+		return errPublicKeySize
+	}
+	if isZeroBytes(uncompressed) {
+		return errZeroPublicKey
+	}
+	if isZeroBytes(uncompressed[1:]) && uncompressed[0] == 0x40 {
+		return errInfinitePublicKey
+	}
+	if uncompressed[0]&uncompressedFlagMask != 0 {
+		return errPublicKeyFlags
+	}
+	return nil
+}
+
+func checkUncompressedSignature(uncompressed []byte, size int) error {
+	if len(uncompressed) != size {
+		return errSignatureSize
+	}
+	if isZeroBytes(uncompressed) {
+		return errZeroSignature
+	}
+	if isZeroBytes(uncompressed[1:]) && uncompressed[0] == 0x40 {
+		return errInfiniteSignature
+	}
+	if uncompressed[0]&uncompressedFlagMask != 0 {
+		return errSignatureFlags
+	}
+	return nil
+}
+
+func isZeroBytes(in []byte) bool {
+	for _, b := range in {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,68 @@
+package cross_bls
+
+// Variant selects the groups that public keys and signatures live in.
+type Variant int
+
+const (
+	// MinPublicKeySize places public keys in G1 and signatures in G2.
+	MinPublicKeySize Variant = iota
+	// MinSignatureSize places signatures in G1 and public keys in G2.
+	MinSignatureSize
+)
+
+const (
+	MinSigSignatureSize = 48
+	MinSigPublicKeySize = 96
+)
+
+var minSigDST = []byte("BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_POP_")
+
+// PublicKeySize returns compressed public key size of the variant.
+func (variant Variant) PublicKeySize() int {
+	if variant == MinSignatureSize {
+		return MinSigPublicKeySize
+	}
+	return PublicKeySize
+}
+
+// SignatureSize returns compressed signature size of the variant.
+func (variant Variant) SignatureSize() int {
+	if variant == MinSignatureSize {
+		return MinSigSignatureSize
+	}
+	return SignatureSize
+}
+
+// UncompressedPublicKeySize returns uncompressed public key size of the variant.
+func (variant Variant) UncompressedPublicKeySize() int {
+	return 2 * variant.PublicKeySize()
+}
+
+// UncompressedSignatureSize returns uncompressed signature size of the variant.
+func (variant Variant) UncompressedSignatureSize() int {
+	return 2 * variant.SignatureSize()
+}
+
+// publicKeyEncodings returns zero and infinity encodings of public keys of the variant.
+func (variant Variant) publicKeyEncodings() ([]byte, []byte) {
+	if variant == MinSignatureSize {
+		return zeroSignature, infiniteSignature
+	}
+	return zeroPublicKey, infinitePublicKey
+}
+
+// signatureEncodings returns zero and infinity encodings of signatures of the variant.
+func (variant Variant) signatureEncodings() ([]byte, []byte) {
+	if variant == MinSignatureSize {
+		return zeroPublicKey, infinitePublicKey
+	}
+	return zeroSignature, infiniteSignature
+}
+
+// hashGroup returns the tag fragment of the group that messages are hashed to.
+func (variant Variant) hashGroup() string {
+	if variant == MinSignatureSize {
+		return "G1"
+	}
+	return "G2"
+}
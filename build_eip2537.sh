@@ -8,3 +8,9 @@ cd ../..
 mkdir -p eip2537/test_vectors
 cp -r blst_eip2537/test_vectors/* eip2537/test_vectors/
 
+mkdir -p eip2537/test_vectors/final
+for vector in blsG1Add blsG1Mul blsG1MultiExp blsG2Add blsG2Mul blsG2MultiExp blsPairing blsMapG1 blsMapG2; do
+	for file in $vector fail-$vector; do
+		curl -sSf -o eip2537/test_vectors/final/$file.json https://raw.githubusercontent.com/ethereum/go-ethereum/v1.15.0/core/vm/testdata/precompiles/$file.json
+	done
+done
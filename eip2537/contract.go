@@ -0,0 +1,65 @@
+package cross_eip2537
+
+// PrecompiledContract is the precompile interface of go-ethereum.
+type PrecompiledContract interface {
+	RequiredGas(input []byte) uint64
+	Run(input []byte) ([]byte, error)
+}
+
+// Contract prices an operation with a gas schedule and runs it with a precompile of either backend.
+type Contract struct {
+	Op         Operation
+	Precompile Precompile
+	Schedule   *GasSchedule
+}
+
+func (c *Contract) RequiredGas(input []byte) uint64 {
+	return c.Schedule.RequiredGas(c.Op, input)
+}
+
+func (c *Contract) Run(input []byte) ([]byte, error) {
+	return c.Precompile(input)
+}
+
+// addressOperations maps addresses of the final specification to the operations priced by gas schedules.
+var addressOperations = map[byte]Operation{
+	AddressG1Add:        OpG1Add,
+	AddressG1MSM:        OpG1MSM,
+	AddressG2Add:        OpG2Add,
+	AddressG2MSM:        OpG2MSM,
+	AddressPairingCheck: OpPairing,
+	AddressMapFpToG1:    OpMapFpToG1,
+	AddressMapFp2ToG2:   OpMapFp2ToG2,
+}
+
+// Contracts wraps precompiles keyed by address, as returned by KilicPrecompiles or BLSTPrecompiles,
+// into contracts priced with the final gas schedule. Precompiles at unknown addresses are skipped.
+func Contracts(precompiles map[byte]Precompile) map[byte]PrecompiledContract {
+	contracts := make(map[byte]PrecompiledContract, len(precompiles))
+	schedule := FinalGasSchedule()
+	for address, precompile := range precompiles {
+		op, ok := addressOperations[address]
+		if !ok {
+			continue
+		}
+		contracts[address] = &Contract{Op: op, Precompile: precompile, Schedule: schedule}
+	}
+	return contracts
+}
+
+// KilicContracts returns contracts of the final specification implemented with kilic, keyed by address.
+func KilicContracts() map[byte]PrecompiledContract {
+	return Contracts(KilicPrecompiles())
+}
+
+// BLSTContracts returns contracts of the final specification implemented with blst, keyed by address.
+func BLSTContracts() map[byte]PrecompiledContract {
+	return Contracts(BLSTPrecompiles())
+}
+
+// ContractAddress returns the 20 bytes address of a precompile, which converts to common.Address of go-ethereum.
+func ContractAddress(address byte) [20]byte {
+	var out [20]byte
+	out[19] = address
+	return out
+}
@@ -0,0 +1,239 @@
+package cross_eip2537
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"math/rand"
+	"os"
+	"sort"
+	"testing"
+	"time"
+
+	kilic "github.com/kilic/bls12-381"
+)
+
+var differentialSeed = flag.Int64("seed", 0, "seed of random inputs of differential tests, zero picks one")
+var differentialRounds = flag.Int("rounds", 20, "number of random inputs per operation of differential tests")
+
+// differentialOp describes an operation with its test vectors and a generator of valid inputs.
+// Inputs of repeated operations are concatenations of slices, each generated by slice.
+type differentialOp struct {
+	name     string
+	files    []string
+	slice    func(r *rand.Rand) []byte
+	repeated bool
+	// subgroup are points of each slice which can be replaced by a point out of subgroup
+	subgroup []subgroupSlot
+}
+
+// subgroupSlot is a point at offset of a slice with a generator of points out of subgroup.
+type subgroupSlot struct {
+	offset int
+	point  func(t *testing.T) []byte
+}
+
+var g1Slot = []subgroupSlot{{0, nonSubgroupG1Point}}
+var g2Slot = []subgroupSlot{{0, nonSubgroupG2Point}}
+
+var differentialOps = []differentialOp{
+	{"G1Add", []string{"blsG1Add", "fail-blsG1Add"}, concat(randG1, randG1), false, g1Slot},
+	{"G1Mul", []string{"blsG1Mul", "fail-blsG1Mul"}, concat(randG1, randScalar), false, g1Slot},
+	{"G1MultiExp", []string{"blsG1MultiExp", "fail-blsG1MultiExp"}, concat(randG1, randScalar), true, g1Slot},
+	{"G1MSM", []string{"blsG1Mul", "blsG1MultiExp", "fail-blsG1MultiExp"}, concat(randG1, randScalar), true, g1Slot},
+	{"G2Add", []string{"blsG2Add", "fail-blsG2Add"}, concat(randG2, randG2), false, g2Slot},
+	{"G2Mul", []string{"blsG2Mul", "fail-blsG2Mul"}, concat(randG2, randScalar), false, g2Slot},
+	{"G2MultiExp", []string{"blsG2MultiExp", "fail-blsG2MultiExp"}, concat(randG2, randScalar), true, g2Slot},
+	{"G2MSM", []string{"blsG2Mul", "blsG2MultiExp", "fail-blsG2MultiExp"}, concat(randG2, randScalar), true, g2Slot},
+	{"Pairing", []string{"blsPairing", "fail-blsPairing"}, concat(randG1, randG2), true, []subgroupSlot{{0, nonSubgroupG1Point}, {128, nonSubgroupG2Point}}},
+	{"MapFpToG1", []string{"blsMapG1", "fail-blsMapG1"}, randFp, false, nil},
+	{"MapFp2ToG2", []string{"blsMapG2", "fail-blsMapG2"}, concat(randFp, randFp), false, nil},
+}
+
+func concat(generators ...func(r *rand.Rand) []byte) func(r *rand.Rand) []byte {
+	return func(r *rand.Rand) []byte {
+		out := []byte{}
+		for _, generate := range generators {
+			out = append(out, generate(r)...)
+		}
+		return out
+	}
+}
+
+func randScalar(r *rand.Rand) []byte {
+	out := make([]byte, 32)
+	r.Read(out)
+	return out
+}
+
+func randFp(r *rand.Rand) []byte {
+	fe := new(big.Int).Rand(r, fieldModulus)
+	out := make([]byte, 64)
+	fe.FillBytes(out[16:])
+	return out
+}
+
+func randG1(r *rand.Rand) []byte {
+	g := kilic.NewG1()
+	p := g.New()
+	g.MulScalar(p, g.One(), new(kilicScalar).FromBytes(randScalar(r)))
+	return encodeG1Point(g.ToBytes(p))
+}
+
+func randG2(r *rand.Rand) []byte {
+	g := kilic.NewG2()
+	p := g.New()
+	g.MulScalar(p, g.One(), new(kilicScalar).FromBytes(randScalar(r)))
+	return encodeG2Point(g.ToBytes(p))
+}
+
+// differentialInputs returns valid inputs and inputs that break each rule of the encoding.
+func differentialInputs(t *testing.T, op differentialOp, r *rand.Rand) [][]byte {
+	nonSubgroup := make([][]byte, len(op.subgroup))
+	for i, slot := range op.subgroup {
+		nonSubgroup[i] = slot.point(t)
+	}
+	inputs := [][]byte{}
+	for i := 0; i < *differentialRounds; i++ {
+		input := op.slice(r)
+		if op.repeated {
+			for k := r.Intn(4); k > 0; k-- {
+				input = append(input, op.slice(r)...)
+			}
+		}
+		inputs = append(inputs, input)
+		mutate := func(f func(input []byte)) {
+			mutated := append([]byte{}, input...)
+			f(mutated)
+			inputs = append(inputs, mutated)
+		}
+		inputs = append(inputs, input[:len(input)-1], append(append([]byte{}, input...), 0))
+		// top bytes
+		mutate(func(input []byte) { input[r.Intn(16)] = byte(r.Intn(255) + 1) })
+		// not canonical
+		mutate(func(input []byte) { fieldModulus.FillBytes(input[16:64]) })
+		// likely not on curve
+		mutate(func(input []byte) { input[63] ^= 1 })
+		// random bytes
+		mutate(func(input []byte) { r.Read(input) })
+		for i, slot := range op.subgroup {
+			mutate(func(input []byte) { copy(input[slot.offset:], nonSubgroup[i]) })
+		}
+	}
+	return inputs
+}
+
+// vectorInputs returns inputs of test vectors in file. Missing vectors are logged and skipped,
+// so random inputs are still run before build_eip2537.sh fetches vectors.
+func vectorInputs(t *testing.T, file string) [][]byte {
+	test_json, err := ioutil.ReadFile("./test_vectors/" + file + ".json")
+	if os.IsNotExist(err) {
+		t.Logf("skipping test_vectors/%s.json, which is missing, build_eip2537.sh fetches test vectors", file)
+		return nil
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tests []precompiledFailureTest
+	if err := json.Unmarshal(test_json, &tests); err != nil {
+		t.Fatal(err)
+	}
+	inputs := [][]byte{}
+	for _, test := range tests {
+		input, err := hex.DecodeString(test.Input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		inputs = append(inputs, input)
+	}
+	return inputs
+}
+
+type differentialResult struct {
+	output []byte
+	err    error
+}
+
+func (result differentialResult) String() string {
+	if result.err != nil {
+		return fmt.Sprintf("error %v", result.err)
+	}
+	return hex.EncodeToString(result.output)
+}
+
+// differentialBackendNames returns names of every registered backend. Unlike other tests,
+// differential tests run every backend regardless of -lib.
+func differentialBackendNames() []string {
+	names := []string{}
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// diverges runs input through every backend and returns results if backends disagree
+// on the output or on whether input is rejected.
+func diverges(op string, input []byte) ([]differentialResult, bool) {
+	results := []differentialResult{}
+	for _, name := range differentialBackendNames() {
+		output, err := backends[name][op](input)
+		results = append(results, differentialResult{output, err})
+	}
+	for _, result := range results[1:] {
+		if (result.err == nil) != (results[0].err == nil) || !bytes.Equal(result.output, results[0].output) {
+			return results, true
+		}
+	}
+	return results, false
+}
+
+// minimize drops slices of a diverging input of a repeated operation while it still diverges.
+func minimize(op differentialOp, input []byte) []byte {
+	size := len(op.slice(rand.New(rand.NewSource(0))))
+	if !op.repeated || len(input)%size != 0 {
+		return input
+	}
+	for off := 0; off < len(input) && len(input) > size; {
+		reduced := append(append([]byte{}, input[:off]...), input[off+size:]...)
+		if _, ok := diverges(op.name, reduced); ok {
+			input = reduced
+			continue
+		}
+		off += size
+	}
+	return input
+}
+
+func TestDifferential(t *testing.T) {
+	seed := *differentialSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	for _, op := range differentialOps {
+		op := op
+		t.Run(op.name, func(t *testing.T) {
+			r := rand.New(rand.NewSource(seed))
+			inputs := differentialInputs(t, op, r)
+			for _, file := range op.files {
+				inputs = append(inputs, vectorInputs(t, file)...)
+			}
+			for _, input := range inputs {
+				if _, ok := diverges(op.name, input); !ok {
+					continue
+				}
+				input = minimize(op, input)
+				results, _ := diverges(op.name, input)
+				message := fmt.Sprintf("backends diverge on %s with -seed %d\ninput: %x", op.name, seed, input)
+				for i, name := range differentialBackendNames() {
+					message += fmt.Sprintf("\n%s: %v", name, results[i])
+				}
+				t.Error(message)
+			}
+		})
+	}
+}
@@ -1,17 +1,36 @@
 package cross_eip2537
 
-import "errors"
+import "math/big"
+
+// Addresses of EIP-2537 precompiles in the final specification.
+// G1Mul and G2Mul of the 2020 draft are replaced by G1MSM and G2MSM with a single pair.
+const (
+	AddressG1Add        byte = 0x0b
+	AddressG1MSM        byte = 0x0c
+	AddressG2Add        byte = 0x0d
+	AddressG2MSM        byte = 0x0e
+	AddressPairingCheck byte = 0x0f
+	AddressMapFpToG1    byte = 0x10
+	AddressMapFp2ToG2   byte = 0x11
+)
+
+// Precompile runs an EIP-2537 operation on its encoded input and returns the encoded output.
+type Precompile func(input []byte) ([]byte, error)
 
 func decodeFieldElement(in []byte) ([]byte, error) {
 	if len(in) != 64 {
-		return nil, errors.New("invalid field element length")
+		return nil, ErrInvalidInputLength
 	}
 	// check top bytes
 	for i := 0; i < 16; i++ {
 		if in[i] != byte(0x00) {
-			return nil, errors.New("invalid field element top bytes")
+			return nil, ErrFieldElementTopBytes
 		}
 	}
+	// check that field element is less than modulus
+	if new(big.Int).SetBytes(in[16:]).Cmp(fieldModulus) >= 0 {
+		return nil, ErrFieldElementNotCanonical
+	}
 	out := make([]byte, 48)
 	copy(out[:], in[16:])
 	return out, nil
@@ -19,7 +38,7 @@ func decodeFieldElement(in []byte) ([]byte, error) {
 
 func decodeG1Point(in []byte) ([]byte, error) {
 	if len(in) != 128 {
-		return nil, errors.New("invalid g1 point length")
+		return nil, ErrInvalidInputLength
 	}
 	pointBytes := make([]byte, 96)
 	// decode x
@@ -61,7 +80,7 @@ func encodeG2Point(outRaw []byte) []byte {
 
 func decodeG2Point(in []byte) ([]byte, error) {
 	if len(in) != 256 {
-		return nil, errors.New("invalid g2 point length")
+		return nil, ErrInvalidInputLength
 	}
 	pointBytes := make([]byte, 192)
 	x0Bytes, err := decodeFieldElement(in[:64])
@@ -1,41 +1,222 @@
 package cross_eip2537
 
 import (
+	"encoding/hex"
+	"fmt"
+
 	blst "github.com/sean-sn/blst_eip2537/go"
 )
 
+// groupOrder is the order of G1 and G2 subgroups encoded as an EIP-2537 scalar.
+var groupOrder, _ = hex.DecodeString("73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001")
+
 func BLSTG1Add(input []byte) ([]byte, error) {
-	return blst.G1Add(input)
+	return blstRun(blst.G1Add, input, g1AddInput)
 }
 
 func BLSTG1Mul(input []byte) ([]byte, error) {
-	return blst.G1Mul(input)
+	return blstRun(blst.G1Mul, input, g1MulInput)
 }
 
 func BLSTG1MultiExp(input []byte) ([]byte, error) {
-	return blst.G1Multiexp(input)
+	return blstRun(blst.G1Multiexp, input, g1MultiExpInput)
+}
+
+func BLSTG1MSM(input []byte) ([]byte, error) {
+	if len(input) == 0 || len(input)%160 != 0 {
+		return nil, ErrInvalidInputLength
+	}
+	for off := 0; off < len(input); off += 160 {
+		if err := blstSubgroupCheck(input[off:off+128], blst.G1Mul); err != nil {
+			return nil, err
+		}
+	}
+	return blstRun(blst.G1Multiexp, input, g1MultiExpInput)
 }
 
 func BLSTG2Add(input []byte) ([]byte, error) {
-	return blst.G2Add(input)
+	return blstRun(blst.G2Add, input, g2AddInput)
 }
 
 func BLSTG2Mul(input []byte) ([]byte, error) {
-	return blst.G2Mul(input)
+	return blstRun(blst.G2Mul, input, g2MulInput)
 }
 
 func BLSTG2MultiExp(input []byte) ([]byte, error) {
-	return blst.G2Multiexp(input)
+	return blstRun(blst.G2Multiexp, input, g2MultiExpInput)
+}
+
+func BLSTG2MSM(input []byte) ([]byte, error) {
+	if len(input) == 0 || len(input)%288 != 0 {
+		return nil, ErrInvalidInputLength
+	}
+	for off := 0; off < len(input); off += 288 {
+		if err := blstSubgroupCheck(input[off:off+256], blst.G2Mul); err != nil {
+			return nil, err
+		}
+	}
+	return blstRun(blst.G2Multiexp, input, g2MultiExpInput)
 }
 
 func BLSTPairing(input []byte) ([]byte, error) {
-	return blst.Pairing(input)
+	if len(input) == 0 || len(input)%384 != 0 {
+		return nil, ErrInvalidInputLength
+	}
+	// pairs are checked in the order kilic decodes them, points of a pair before their subgroups
+	for off := 0; off < len(input); off += 384 {
+		if err := blstPointError(input[off : off+128]); err != nil {
+			return nil, err
+		}
+		if err := blstPointError(input[off+128 : off+384]); err != nil {
+			return nil, err
+		}
+		if err := blstSubgroupCheck(input[off:off+128], blst.G1Mul); err != nil {
+			return nil, err
+		}
+		if err := blstSubgroupCheck(input[off+128:off+384], blst.G2Mul); err != nil {
+			return nil, err
+		}
+	}
+	return blstRun(blst.Pairing, input, pairingInput)
 }
 
 func BLSTMapG1(input []byte) ([]byte, error) {
-	return blst.MapFpToG1(input)
+	return blstRun(blst.MapFpToG1, input, mapG1Input)
 }
 
 func BLSTMapG2(input []byte) ([]byte, error) {
-	return blst.MapFp2ToG2(input)
+	return blstRun(blst.MapFp2ToG2, input, mapG2Input)
+}
+
+// blstSubgroupCheck returns an error if point is malformed, not on the curve or not in the subgroup.
+// blst_eip2537 bindings predate subgroup checks of MSM inputs and expose no other way to check,
+// so points are multiplied by the group order, which results in the point at infinity only for
+// points in the subgroup. Pairing inputs are checked the same way. MSM of k pairs thus costs k more scalar multiplications than the final
+// gas schedule prices, which is about twice the price with few pairs and more with many pairs,
+// as the discount does not apply to the check. BenchmarkG1MSMGas and BenchmarkG2MSMGas report
+// the gas throughput against multiexponentiation without the check.
+func blstSubgroupCheck(point []byte, mul func([]byte) ([]byte, error)) error {
+	if err := blstPointError(point); err != nil {
+		return err
+	}
+	out, err := mul(append(append([]byte{}, point...), groupOrder...))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	if !isZero(out) {
+		return ErrPointNotInSubgroup
+	}
+	return nil
+}
+
+// blstPointError returns an error if a G1 point of 128 bytes or a G2 point of 256 bytes
+// has a malformed field element or is not on the curve.
+func blstPointError(point []byte) error {
+	for off := 0; off < len(point); off += 64 {
+		if _, err := decodeFieldElement(point[off : off+64]); err != nil {
+			return err
+		}
+	}
+	onCurve := g1OnCurve
+	if len(point) == 256 {
+		onCurve = g2OnCurve
+	}
+	if !onCurve(point) {
+		return ErrPointNotOnCurve
+	}
+	return nil
+}
+
+// blstInput describes the layout of an input, which is a single slice or a concatenation of
+// k > 0 slices of size bytes. Offsets of G1 points, G2 points and field elements are relative to a slice.
+type blstInput struct {
+	size       int
+	repeated   bool
+	g1, g2, fp []int
+}
+
+var (
+	g1AddInput      = blstInput{size: 256, g1: []int{0, 128}}
+	g1MulInput      = blstInput{size: 160, g1: []int{0}}
+	g1MultiExpInput = blstInput{size: 160, repeated: true, g1: []int{0}}
+	g2AddInput      = blstInput{size: 512, g2: []int{0, 256}}
+	g2MulInput      = blstInput{size: 288, g2: []int{0}}
+	g2MultiExpInput = blstInput{size: 288, repeated: true, g2: []int{0}}
+	pairingInput    = blstInput{size: 384, repeated: true, g1: []int{0}, g2: []int{128}}
+	mapG1Input      = blstInput{size: 64, fp: []int{0}}
+	mapG2Input      = blstInput{size: 128, fp: []int{0, 64}}
+)
+
+// blstRun runs a blst_eip2537 operation. Its errors are not typed, so rejected inputs are classified
+// against their layout here and the error of the first malformed element is returned. Points out of
+// subgroup are rejected by blstSubgroupCheck before operations that require it, so the blst error of
+// an input with no malformed element is wrapped in ErrInvalidInput.
+func blstRun(op func([]byte) ([]byte, error), input []byte, layout blstInput) ([]byte, error) {
+	out, err := op(input)
+	if err == nil {
+		return out, nil
+	}
+	if classified := blstInputError(input, layout); classified != nil {
+		return nil, classified
+	}
+	return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+}
+
+// blstInputError returns the error of the first malformed element of input, checking points
+// with big.Int arithmetic rather than blst operations.
+func blstInputError(input []byte, layout blstInput) error {
+	if layout.repeated {
+		if len(input) == 0 || len(input)%layout.size != 0 {
+			return ErrInvalidInputLength
+		}
+	} else if len(input) != layout.size {
+		return ErrInvalidInputLength
+	}
+	for off := 0; off < len(input); off += layout.size {
+		for _, i := range layout.fp {
+			if _, err := decodeFieldElement(input[off+i : off+i+64]); err != nil {
+				return err
+			}
+		}
+		for _, i := range layout.g1 {
+			if err := blstPointError(input[off+i : off+i+128]); err != nil {
+				return err
+			}
+		}
+		for _, i := range layout.g2 {
+			if err := blstPointError(input[off+i : off+i+256]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func init() {
+	registerBackend(libBLST, map[string]Precompile{
+		"G1Add":      BLSTG1Add,
+		"G1Mul":      BLSTG1Mul,
+		"G1MultiExp": BLSTG1MultiExp,
+		"G1MSM":      BLSTG1MSM,
+		"G2Add":      BLSTG2Add,
+		"G2Mul":      BLSTG2Mul,
+		"G2MultiExp": BLSTG2MultiExp,
+		"G2MSM":      BLSTG2MSM,
+		"Pairing":    BLSTPairing,
+		"MapFpToG1":  BLSTMapG1,
+		"MapFp2ToG2": BLSTMapG2,
+	})
+}
+
+// BLSTPrecompiles returns precompiles of the final specification implemented with blst, keyed by address.
+func BLSTPrecompiles() map[byte]Precompile {
+	return map[byte]Precompile{
+		AddressG1Add:        BLSTG1Add,
+		AddressG1MSM:        BLSTG1MSM,
+		AddressG2Add:        BLSTG2Add,
+		AddressG2MSM:        BLSTG2MSM,
+		AddressPairingCheck: BLSTPairing,
+		AddressMapFpToG1:    BLSTMapG1,
+		AddressMapFp2ToG2:   BLSTMapG2,
+	}
 }
@@ -8,12 +8,14 @@ type kilicPointG1 = kilic.PointG1
 type kilicPointG2 = kilic.PointG2
 type kilicScalar = kilic.Fr
 
+// Field elements are decoded before kilic decodes points, so kilic only rejects points that are not on the curve.
+
 func KilicG1Add(input []byte) ([]byte, error) {
 	// Implements EIP-2537 G1Add precompile.
 	// > G1 addition call expects `256` bytes as an input that is interpreted as byte concatenation of two G1 points (`128` bytes each).
 	// > Output is an encoding of addition operation result - single G1 point (`128` bytes).
 	if len(input) != 256 {
-		return nil, errEIP2537InvalidInputLength
+		return nil, ErrInvalidInputLength
 	}
 	var err error
 	var p0, p1 *kilicPointG1
@@ -27,7 +29,7 @@ func KilicG1Add(input []byte) ([]byte, error) {
 		return nil, err
 	}
 	if p0, err = g.FromBytes(p0Bytes); err != nil {
-		return nil, err
+		return nil, ErrPointNotOnCurve
 	}
 	// Decode G1 point p_1
 	p1Bytes, err := decodeG1Point(input[128:])
@@ -35,7 +37,7 @@ func KilicG1Add(input []byte) ([]byte, error) {
 		return nil, err
 	}
 	if p1, err = g.FromBytes(p1Bytes); err != nil {
-		return nil, err
+		return nil, ErrPointNotOnCurve
 	}
 
 	// Compute r = p_0 + p_1
@@ -51,7 +53,7 @@ func KilicG1Mul(input []byte) ([]byte, error) {
 	// > G1 multiplication call expects `160` bytes as an input that is interpreted as byte concatenation of encoding of G1 point (`128` bytes) and encoding of a scalar value (`32` bytes).
 	// > Output is an encoding of multiplication operation result - single G1 point (`128` bytes).
 	if len(input) != 160 {
-		return nil, errEIP2537InvalidInputLength
+		return nil, ErrInvalidInputLength
 	}
 	var err error
 	var p0 *kilicPointG1
@@ -65,7 +67,7 @@ func KilicG1Mul(input []byte) ([]byte, error) {
 		return nil, err
 	}
 	if p0, err = g.FromBytes(pointBytes); err != nil {
-		return nil, err
+		return nil, ErrPointNotOnCurve
 	}
 	// Decode scalar value
 	e := new(kilicScalar).FromBytes(input[128:])
@@ -79,12 +81,24 @@ func KilicG1Mul(input []byte) ([]byte, error) {
 }
 
 func KilicG1MultiExp(input []byte) ([]byte, error) {
-	// Implements EIP-2537 G1MultiExp precompile.
+	// Implements EIP-2537 G1MultiExp precompile of the 2020 draft.
 	// G1 multiplication call expects `160*k` bytes as an input that is interpreted as byte concatenation of `k` slices each of them being a byte concatenation of encoding of G1 point (`128` bytes) and encoding of a scalar value (`32` bytes).
 	// Output is an encoding of multiexponentiation operation result - single G1 point (`128` bytes).
+	return kilicG1MultiExp(input, false)
+}
+
+func KilicG1MSM(input []byte) ([]byte, error) {
+	// Implements EIP-2537 G1MSM precompile of the final specification.
+	// > G1 MSM call expects `160*k` (`k` being a positive integer) bytes as an input that is interpreted as byte concatenation of `k` slices each of them being a byte concatenation of encoding of G1 point (`128` bytes) and encoding of a scalar value (`32` bytes).
+	// > Output is an encoding of MSM operation result - single G1 point (`128` bytes).
+	// Points must be in the subgroup, and MSM with `k = 1` replaces G1Mul of the draft.
+	return kilicG1MultiExp(input, true)
+}
+
+func kilicG1MultiExp(input []byte, subgroupCheck bool) ([]byte, error) {
 	k := len(input) / 160
 	if len(input) == 0 || len(input)%160 != 0 {
-		return nil, errEIP2537InvalidInputLength
+		return nil, ErrInvalidInputLength
 	}
 
 	points := make([]*kilicPointG1, k)
@@ -104,7 +118,10 @@ func KilicG1MultiExp(input []byte) ([]byte, error) {
 		}
 		points[i], err = g.FromBytes(pointBytes)
 		if err != nil {
-			return nil, err
+			return nil, ErrPointNotOnCurve
+		}
+		if subgroupCheck && !g.InCorrectSubgroup(points[i]) {
+			return nil, ErrPointNotInSubgroup
 		}
 		// Decode scalar value
 		scalars[i] = new(kilicScalar).FromBytes(input[t1:t2])
@@ -123,7 +140,7 @@ func KilicG2Add(input []byte) ([]byte, error) {
 	// > G2 addition call expects `512` bytes as an input that is interpreted as byte concatenation of two G2 points (`256` bytes each).
 	// > Output is an encoding of addition operation result - single G2 point (`256` bytes).
 	if len(input) != 512 {
-		return nil, errEIP2537InvalidInputLength
+		return nil, ErrInvalidInputLength
 	}
 	var err error
 	var p0, p1 *kilicPointG2
@@ -138,7 +155,7 @@ func KilicG2Add(input []byte) ([]byte, error) {
 		return nil, err
 	}
 	if p0, err = g.FromBytes(p0Bytes); err != nil {
-		return nil, err
+		return nil, ErrPointNotOnCurve
 	}
 	// Decode G2 point p_1
 	p1Bytes, err := decodeG2Point(input[256:])
@@ -146,7 +163,7 @@ func KilicG2Add(input []byte) ([]byte, error) {
 		return nil, err
 	}
 	if p1, err = g.FromBytes(p1Bytes); err != nil {
-		return nil, err
+		return nil, ErrPointNotOnCurve
 	}
 
 	// Compute r = p_0 + p_1
@@ -162,7 +179,7 @@ func KilicG2Mul(input []byte) ([]byte, error) {
 	// > G2 multiplication call expects `288` bytes as an input that is interpreted as byte concatenation of encoding of G2 point (`256` bytes) and encoding of a scalar value (`32` bytes).
 	// > Output is an encoding of multiplication operation result - single G2 point (`256` bytes).
 	if len(input) != 288 {
-		return nil, errEIP2537InvalidInputLength
+		return nil, ErrInvalidInputLength
 	}
 	var p0 *kilicPointG2
 
@@ -175,7 +192,7 @@ func KilicG2Mul(input []byte) ([]byte, error) {
 		return nil, err
 	}
 	if p0, err = g.FromBytes(pointBytes); err != nil {
-		return nil, err
+		return nil, ErrPointNotOnCurve
 	}
 	// Decode scalar value
 	e := new(kilicScalar).FromBytes(input[256:])
@@ -189,12 +206,24 @@ func KilicG2Mul(input []byte) ([]byte, error) {
 }
 
 func KilicG2MultiExp(input []byte) ([]byte, error) {
-	// Implements EIP-2537 G2MultiExp precompile logic
+	// Implements EIP-2537 G2MultiExp precompile logic of the 2020 draft.
 	// > G2 multiplication call expects `288*k` bytes as an input that is interpreted as byte concatenation of `k` slices each of them being a byte concatenation of encoding of G2 point (`256` bytes) and encoding of a scalar value (`32` bytes).
 	// > Output is an encoding of multiexponentiation operation result - single G2 point (`256` bytes).
+	return kilicG2MultiExp(input, false)
+}
+
+func KilicG2MSM(input []byte) ([]byte, error) {
+	// Implements EIP-2537 G2MSM precompile logic of the final specification.
+	// > G2 MSM call expects `288*k` (`k` being a positive integer) bytes as an input that is interpreted as byte concatenation of `k` slices each of them being a byte concatenation of encoding of G2 point (`256` bytes) and encoding of a scalar value (`32` bytes).
+	// > Output is an encoding of MSM operation result - single G2 point (`256` bytes).
+	// Points must be in the subgroup, and MSM with `k = 1` replaces G2Mul of the draft.
+	return kilicG2MultiExp(input, true)
+}
+
+func kilicG2MultiExp(input []byte, subgroupCheck bool) ([]byte, error) {
 	k := len(input) / 288
 	if len(input) == 0 || len(input)%288 != 0 {
-		return nil, errEIP2537InvalidInputLength
+		return nil, ErrInvalidInputLength
 	}
 	points := make([]*kilicPointG2, k)
 	scalars := make([]*kilicScalar, k)
@@ -206,14 +235,17 @@ func KilicG2MultiExp(input []byte) ([]byte, error) {
 	for i := 0; i < k; i++ {
 		off := 288 * i
 		t0, t1, t2 := off, off+256, off+288
-		// Decode G1 point
+		// Decode G2 point
 		pointBytes, err := decodeG2Point(input[t0:t1])
 		if err != nil {
 			return nil, err
 		}
 		points[i], err = g.FromBytes(pointBytes)
 		if err != nil {
-			return nil, err
+			return nil, ErrPointNotOnCurve
+		}
+		if subgroupCheck && !g.InCorrectSubgroup(points[i]) {
+			return nil, ErrPointNotInSubgroup
 		}
 		// Decode scalar value
 		scalars[i] = new(kilicScalar).FromBytes(input[t1:t2])
@@ -236,7 +268,7 @@ func KilicPairing(input []byte) ([]byte, error) {
 	// > (which is equivalent of Big Endian encoding of Solidity values `uint256(1)` and `uin256(0)` respectively).
 	k := len(input) / 384
 	if len(input) == 0 || len(input)%384 != 0 {
-		return nil, errEIP2537InvalidInputLength
+		return nil, ErrInvalidInputLength
 	}
 
 	// Initialize BLS12-381 pairing engine
@@ -255,7 +287,7 @@ func KilicPairing(input []byte) ([]byte, error) {
 		}
 		p1, err := g1.FromBytes(p1Bytes)
 		if err != nil {
-			return nil, err
+			return nil, ErrPointNotOnCurve
 		}
 		// Decode G2 point
 		p2Bytes, err := decodeG2Point(input[t1:t2])
@@ -264,16 +296,16 @@ func KilicPairing(input []byte) ([]byte, error) {
 		}
 		p2, err := g2.FromBytes(p2Bytes)
 		if err != nil {
-			return nil, err
+			return nil, ErrPointNotOnCurve
 		}
 
 		// 'point is on curve' check already done,
 		// Here we need to apply subgroup checks.
 		if !g1.InCorrectSubgroup(p1) {
-			return nil, errEIP2537G1PointSubgroup
+			return nil, ErrPointNotInSubgroup
 		}
 		if !g2.InCorrectSubgroup(p2) {
-			return nil, errEIP2537G2PointSubgroup
+			return nil, ErrPointNotInSubgroup
 		}
 
 		// Update pairing engine with G1 and G2 ponits
@@ -294,7 +326,7 @@ func KilicMapG1(input []byte) ([]byte, error) {
 	// > Field-to-curve call expects `64` bytes an an input that is interpreted as a an element of the base field.
 	// > Output of this call is `128` bytes and is G1 point following respective encoding rules.
 	if len(input) != 64 {
-		return nil, errEIP2537InvalidInputLength
+		return nil, ErrInvalidInputLength
 	}
 
 	// Decode input field element
@@ -321,7 +353,7 @@ func KilicMapG2(input []byte) ([]byte, error) {
 	// > Field-to-curve call expects `128` bytes an an input that is interpreted as a an element of the quadratic extension field.
 	// > Output of this call is `256` bytes and is G2 point following respective encoding rules.
 	if len(input) != 128 {
-		return nil, errEIP2537InvalidInputLength
+		return nil, ErrInvalidInputLength
 	}
 
 	// Decode input field element
@@ -349,3 +381,32 @@ func KilicMapG2(input []byte) ([]byte, error) {
 	// Encode the G2 point to 256 bytes
 	return encodeG2Point(g.ToBytes(r)), nil
 }
+
+func init() {
+	registerBackend(libKilic, map[string]Precompile{
+		"G1Add":      KilicG1Add,
+		"G1Mul":      KilicG1Mul,
+		"G1MultiExp": KilicG1MultiExp,
+		"G1MSM":      KilicG1MSM,
+		"G2Add":      KilicG2Add,
+		"G2Mul":      KilicG2Mul,
+		"G2MultiExp": KilicG2MultiExp,
+		"G2MSM":      KilicG2MSM,
+		"Pairing":    KilicPairing,
+		"MapFpToG1":  KilicMapG1,
+		"MapFp2ToG2": KilicMapG2,
+	})
+}
+
+// KilicPrecompiles returns precompiles of the final specification implemented with kilic, keyed by address.
+func KilicPrecompiles() map[byte]Precompile {
+	return map[byte]Precompile{
+		AddressG1Add:        KilicG1Add,
+		AddressG1MSM:        KilicG1MSM,
+		AddressG2Add:        KilicG2Add,
+		AddressG2MSM:        KilicG2MSM,
+		AddressPairingCheck: KilicPairing,
+		AddressMapFpToG1:    KilicMapG1,
+		AddressMapFp2ToG2:   KilicMapG2,
+	}
+}
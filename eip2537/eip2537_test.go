@@ -3,12 +3,17 @@ package cross_eip2537
 import (
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io/ioutil"
+	"math/big"
+	"math/rand"
 	"os"
 	"testing"
 	"time"
+
+	kilic "github.com/kilic/bls12-381"
 )
 
 type precompileRunner func([]byte) ([]byte, error)
@@ -22,6 +27,9 @@ var G2MultiExp precompileRunner
 var Pairing precompileRunner
 var MapFpToG1 precompileRunner
 var MapFp2ToG2 precompileRunner
+var G1MSM precompileRunner
+var G2MSM precompileRunner
+var precompiles map[byte]Precompile
 
 func TestMain(m *testing.M) {
 	_library := flag.String("lib", "none", "select a library")
@@ -39,6 +47,9 @@ func TestMain(m *testing.M) {
 		Pairing = BLSTPairing
 		MapFpToG1 = BLSTMapG1
 		MapFp2ToG2 = BLSTMapG2
+		G1MSM = BLSTG1MSM
+		G2MSM = BLSTG2MSM
+		precompiles = BLSTPrecompiles()
 	case libKilic:
 		G1Add = KilicG1Add
 		G1Mul = KilicG1Mul
@@ -49,6 +60,9 @@ func TestMain(m *testing.M) {
 		Pairing = KilicPairing
 		MapFpToG1 = KilicMapG1
 		MapFp2ToG2 = KilicMapG2
+		G1MSM = KilicG1MSM
+		G2MSM = KilicG2MSM
+		precompiles = KilicPrecompiles()
 	}
 
 	_init()
@@ -75,6 +89,21 @@ type precompiledFailureTest struct {
 	Name          string
 }
 
+// expectedErrorClasses maps expected error messages of failure vectors to error classes. Draft vectors
+// follow go-ethereum errors of the draft, and final vectors those of go-ethereum v1.15.0. Messages that
+// are not listed fail the test, so new wording is classified here rather than matched loosely.
+var expectedErrorClasses = map[string]error{
+	"invalid input length":                ErrInvalidInputLength,
+	"invalid field element top bytes":     ErrFieldElementTopBytes,
+	"must be less than modulus":           ErrFieldElementNotCanonical,
+	"invalid fp.Element encoding":         ErrFieldElementNotCanonical,
+	"point is not on curve":               ErrPointNotOnCurve,
+	"invalid point: not on curve":         ErrPointNotOnCurve,
+	"point is not on correct subgroup":    ErrPointNotInSubgroup,
+	"g1 point is not on correct subgroup": ErrPointNotInSubgroup,
+	"g2 point is not on correct subgroup": ErrPointNotInSubgroup,
+}
+
 func testJson(file_path string, expect_success bool,
 	test_function precompileRunner, t *testing.T) {
 
@@ -127,6 +156,10 @@ func testJson(file_path string, expect_success bool,
 				if test_err == nil {
 					t.Errorf("Test should have failed with error %v",
 						test.ExpectedError)
+				} else if expected, ok := expectedErrorClasses[test.ExpectedError]; !ok {
+					t.Errorf("Unknown expected error %v", test.ExpectedError)
+				} else if !errors.Is(test_err, expected) {
+					t.Errorf("Expected error %v, got %v", expected, test_err)
 				}
 			})
 		}
@@ -263,6 +296,343 @@ func TestMapFp2ToG2Fail(t *testing.T) {
 	testJson("./test_vectors/fail-blsMapG2.json", false, MapFp2ToG2, t)
 }
 
+// MSM of the final specification with a single pair is multiplication of the draft,
+// so draft vectors of points in the subgroup are valid for MSM.
+func TestG1MSM(t *testing.T) {
+	testJson("./test_vectors/blsG1Mul.json", true, G1MSM, t)
+	testJson("./test_vectors/blsG1MultiExp.json", true, G1MSM, t)
+}
+
+func TestG2MSM(t *testing.T) {
+	testJson("./test_vectors/blsG2Mul.json", true, G2MSM, t)
+	testJson("./test_vectors/blsG2MultiExp.json", true, G2MSM, t)
+}
+
+func TestG1MSMFail(t *testing.T) {
+	testJson("./test_vectors/fail-blsG1MultiExp.json", false, G1MSM, t)
+}
+
+func TestG2MSMFail(t *testing.T) {
+	testJson("./test_vectors/fail-blsG2MultiExp.json", false, G2MSM, t)
+}
+
+// nonSubgroupG1Point returns encoding of a point on G1 curve which is not in the subgroup.
+func nonSubgroupG1Point(t *testing.T) []byte {
+	p, _ := new(big.Int).SetString("1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab", 16)
+	exp := new(big.Int).Div(new(big.Int).Add(p, big.NewInt(1)), big.NewInt(4))
+	g := kilic.NewG1()
+	for x := int64(1); ; x++ {
+		_x := big.NewInt(x)
+		rhs := new(big.Int).Exp(_x, big.NewInt(3), p)
+		rhs.Add(rhs, big.NewInt(4)).Mod(rhs, p)
+		y := new(big.Int).Exp(rhs, exp, p)
+		if new(big.Int).Exp(y, big.NewInt(2), p).Cmp(rhs) != 0 {
+			continue
+		}
+		out := make([]byte, 96)
+		_x.FillBytes(out[:48])
+		y.FillBytes(out[48:])
+		point, err := g.FromBytes(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !g.InCorrectSubgroup(point) {
+			return encodeG1Point(out)
+		}
+	}
+}
+
+// nonSubgroupG2Point returns encoding of a point on G2 curve which is not in the subgroup.
+func nonSubgroupG2Point(t *testing.T) []byte {
+	p, _ := new(big.Int).SetString("1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab", 16)
+	exp := new(big.Int).Div(new(big.Int).Add(p, big.NewInt(1)), big.NewInt(4))
+	sqrt := func(a *big.Int) (*big.Int, bool) {
+		r := new(big.Int).Exp(a, exp, p)
+		return r, new(big.Int).Exp(r, big.NewInt(2), p).Cmp(a) == 0
+	}
+	half := new(big.Int).ModInverse(big.NewInt(2), p)
+	g := kilic.NewG2()
+	for x := int64(1); ; x++ {
+		// rhs = x^3 + 4(u + 1) = c0 + c1 * u
+		_x := big.NewInt(x)
+		c0 := new(big.Int).Exp(_x, big.NewInt(3), p)
+		c0.Add(c0, big.NewInt(4)).Mod(c0, p)
+		c1 := big.NewInt(4)
+		// sqrt(c0 + c1 * u) = y0 + y1 * u where y0^2 = (c0 +- sqrt(c0^2 + c1^2)) / 2 and y1 = c1 / 2y0
+		norm := new(big.Int).Add(new(big.Int).Mul(c0, c0), new(big.Int).Mul(c1, c1))
+		n, ok := sqrt(norm.Mod(norm, p))
+		if !ok {
+			continue
+		}
+		y0, ok := sqrt(new(big.Int).Mod(new(big.Int).Mul(new(big.Int).Add(c0, n), half), p))
+		if !ok {
+			y0, ok = sqrt(new(big.Int).Mod(new(big.Int).Mul(new(big.Int).Sub(c0, n), half), p))
+		}
+		if !ok || y0.Sign() == 0 {
+			continue
+		}
+		y1 := new(big.Int).ModInverse(new(big.Int).Lsh(y0, 1), p)
+		y1.Mul(y1, c1).Mod(y1, p)
+		// kilic encodes c1 before c0
+		out := make([]byte, 192)
+		_x.FillBytes(out[48:96])
+		y1.FillBytes(out[96:144])
+		y0.FillBytes(out[144:])
+		point, err := g.FromBytes(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !g.InCorrectSubgroup(point) {
+			return encodeG2Point(out)
+		}
+	}
+}
+
+func TestG1MSMSubgroup(t *testing.T) {
+	scalar := make([]byte, 32)
+	scalar[31] = 1
+	input := append(nonSubgroupG1Point(t), scalar...)
+	if _, err := G1MultiExp(input); err != nil {
+		t.Fatalf("draft multiexp does not check subgroup: %v", err)
+	}
+	if _, err := G1MSM(input); !errors.Is(err, ErrPointNotInSubgroup) {
+		t.Fatalf("point out of subgroup must be rejected, got %v", err)
+	}
+	// infinity is in the subgroup
+	if _, err := G1MSM(make([]byte, 160)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestG2MSMSubgroup(t *testing.T) {
+	scalar := make([]byte, 32)
+	scalar[31] = 1
+	input := append(nonSubgroupG2Point(t), scalar...)
+	if _, err := G2MultiExp(input); err != nil {
+		t.Fatalf("draft multiexp does not check subgroup: %v", err)
+	}
+	if _, err := G2MSM(input); !errors.Is(err, ErrPointNotInSubgroup) {
+		t.Fatalf("point out of subgroup must be rejected, got %v", err)
+	}
+	// infinity is in the subgroup
+	if _, err := G2MSM(make([]byte, 288)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPrecompileAddresses(t *testing.T) {
+	for address := byte(0x0b); address <= 0x11; address++ {
+		if precompiles[address] == nil {
+			t.Fatalf("no precompile at %#x", address)
+		}
+	}
+	if len(precompiles) != 7 {
+		t.Fatalf("unexpected precompiles %d", len(precompiles))
+	}
+}
+
+func testGasJson(file_path string, schedule *GasSchedule, op Operation, t *testing.T) {
+	test_json, err := ioutil.ReadFile(file_path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tests []precompiledTest
+	if err := json.Unmarshal(test_json, &tests); err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			input, err := hex.DecodeString(test.Input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if gas := schedule.RequiredGas(op, input); gas != test.Gas {
+				t.Errorf("Expected gas %d, got %d", test.Gas, gas)
+			}
+		})
+	}
+}
+
+// test vectors follow the gas schedule of the 2020 draft
+func TestGas(t *testing.T) {
+	testGasJson("./test_vectors/blsG1Add.json", DraftGasSchedule(), OpG1Add, t)
+	testGasJson("./test_vectors/blsG1Mul.json", DraftGasSchedule(), OpG1Mul, t)
+	testGasJson("./test_vectors/blsG1MultiExp.json", DraftGasSchedule(), OpG1MSM, t)
+	testGasJson("./test_vectors/blsG2Add.json", DraftGasSchedule(), OpG2Add, t)
+	testGasJson("./test_vectors/blsG2Mul.json", DraftGasSchedule(), OpG2Mul, t)
+	testGasJson("./test_vectors/blsG2MultiExp.json", DraftGasSchedule(), OpG2MSM, t)
+	testGasJson("./test_vectors/blsPairing.json", DraftGasSchedule(), OpPairing, t)
+	testGasJson("./test_vectors/blsMapG1.json", DraftGasSchedule(), OpMapFpToG1, t)
+	testGasJson("./test_vectors/blsMapG2.json", DraftGasSchedule(), OpMapFp2ToG2, t)
+}
+
+// finalVectors are test vectors of the final specification from go-ethereum, which build_eip2537.sh
+// fetches into test_vectors/final, with the address of the precompile they run against.
+// Multiplication vectors run against MSM, which replaces multiplication in the final specification.
+var finalVectors = []struct {
+	file    string
+	address byte
+}{
+	{"blsG1Add", AddressG1Add},
+	{"blsG1Mul", AddressG1MSM},
+	{"blsG1MultiExp", AddressG1MSM},
+	{"blsG2Add", AddressG2Add},
+	{"blsG2Mul", AddressG2MSM},
+	{"blsG2MultiExp", AddressG2MSM},
+	{"blsPairing", AddressPairingCheck},
+	{"blsMapG1", AddressMapFpToG1},
+	{"blsMapG2", AddressMapFp2ToG2},
+}
+
+func TestFinalVectors(t *testing.T) {
+	if _, err := os.Stat("./test_vectors/final"); os.IsNotExist(err) {
+		t.Skip("test_vectors/final is missing, build_eip2537.sh fetches final vectors")
+	}
+	for _, vector := range finalVectors {
+		precompile := precompileRunner(precompiles[vector.address])
+		testJson("./test_vectors/final/"+vector.file+".json", true, precompile, t)
+		testJson("./test_vectors/final/fail-"+vector.file+".json", false, precompile, t)
+		testGasJson("./test_vectors/final/"+vector.file+".json", FinalGasSchedule(), addressOperations[vector.address], t)
+	}
+}
+
+func TestFinalGas(t *testing.T) {
+	for _, test := range []struct {
+		op       Operation
+		inputLen int
+		gas      uint64
+	}{
+		{OpG1Add, 256, 375},
+		{OpG2Add, 512, 600},
+		{OpG1MSM, 0, 0},
+		{OpG1MSM, 160, 12000},
+		{OpG1MSM, 2 * 160, 22776},
+		{OpG1MSM, 200 * 160, 1245600},
+		{OpG2MSM, 288, 22500},
+		{OpG2MSM, 2 * 288, 45000},
+		{OpPairing, 0, 37700},
+		{OpPairing, 2 * 384, 102900},
+		{OpMapFpToG1, 64, 5500},
+		{OpMapFp2ToG2, 128, 23800},
+	} {
+		if gas := RequiredGas(test.op, make([]byte, test.inputLen)); gas != test.gas {
+			t.Errorf("op %d with input of %d bytes: expected gas %d, got %d", test.op, test.inputLen, test.gas, gas)
+		}
+	}
+	for _, schedule := range []*GasSchedule{FinalGasSchedule(), DraftGasSchedule()} {
+		if len(schedule.G1MSMDiscount) != 128 || len(schedule.G2MSMDiscount) != 128 {
+			t.Fatal("discount tables must have 128 entries")
+		}
+	}
+	// schedules are copies, so callers can not change the gas of RequiredGas and contracts
+	schedule := FinalGasSchedule()
+	schedule.G1Mul = 0
+	schedule.G1MSMDiscount[0] = 0
+	if gas := RequiredGas(OpG1MSM, make([]byte, 160)); gas != 12000 {
+		t.Fatalf("gas schedule is shared, got gas %d", gas)
+	}
+}
+
+func TestContracts(t *testing.T) {
+	var _ PrecompiledContract = &Contract{}
+	contracts := Contracts(precompiles)
+	if len(contracts) != len(precompiles) {
+		t.Fatalf("unexpected contracts %d", len(contracts))
+	}
+	scalar := make([]byte, 32)
+	scalar[31] = 2
+	input := append(make([]byte, 128), scalar...)
+	contract := contracts[AddressG1MSM]
+	if gas := contract.RequiredGas(input); gas != 12000 {
+		t.Fatalf("unexpected gas %d", gas)
+	}
+	expected, err := G1MSM(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, err := contract.Run(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(output) != hex.EncodeToString(expected) {
+		t.Fatalf("contract output %x differs from precompile %x", output, expected)
+	}
+	if gas := contracts[AddressPairingCheck].RequiredGas(make([]byte, 384)); gas != 70300 {
+		t.Fatalf("unexpected pairing gas %d", gas)
+	}
+	if address := ContractAddress(AddressMapFp2ToG2); address != [20]byte{19: 0x11} {
+		t.Fatalf("unexpected address %x", address)
+	}
+}
+
+func TestCurveEquations(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	if !g1OnCurve(make([]byte, 128)) || !g2OnCurve(make([]byte, 256)) {
+		t.Fatal("point at infinity should be on curve")
+	}
+	if !g1OnCurve(nonSubgroupG1Point(t)) {
+		t.Fatal("point out of subgroup should be on curve")
+	}
+	for i := 0; i < 10; i++ {
+		g1, g2 := randG1(r), randG2(r)
+		if !g1OnCurve(g1) || !g2OnCurve(g2) {
+			t.Fatal("random point should be on curve")
+		}
+		g1[127] ^= 1
+		g2[255] ^= 1
+		if g1OnCurve(g1) || g2OnCurve(g2) {
+			t.Fatal("mutated point should not be on curve")
+		}
+	}
+}
+
+func TestErrorClasses(t *testing.T) {
+	modulus, _ := hex.DecodeString("1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab")
+	g1Point := func(x, y []byte) []byte {
+		out := make([]byte, 128)
+		copy(out[64-len(x):64], x)
+		copy(out[128-len(y):], y)
+		return out
+	}
+	topBytes := make([]byte, 128)
+	topBytes[0] = 1
+	pairs := func(points ...[]byte) []byte {
+		input := []byte{}
+		for _, point := range points {
+			input = append(input, point...)
+		}
+		return input
+	}
+	scalar := make([]byte, 32)
+	for _, test := range []struct {
+		name  string
+		run   precompileRunner
+		input []byte
+		err   error
+	}{
+		{"empty", G1Add, []byte{}, ErrInvalidInputLength},
+		{"short", G1Add, make([]byte, 255), ErrInvalidInputLength},
+		{"msm_not_multiple", G1MSM, make([]byte, 161), ErrInvalidInputLength},
+		{"top_bytes", G1Add, pairs(topBytes, make([]byte, 128)), ErrFieldElementTopBytes},
+		{"not_canonical", G1Add, pairs(g1Point(modulus, []byte{2}), make([]byte, 128)), ErrFieldElementNotCanonical},
+		{"not_on_curve", G1Add, pairs(make([]byte, 128), g1Point([]byte{1}, []byte{1})), ErrPointNotOnCurve},
+		{"msm_not_on_curve", G1MSM, pairs(g1Point([]byte{1}, []byte{1}), scalar), ErrPointNotOnCurve},
+		{"g2_msm_not_on_curve", G2MSM, pairs(g1Point([]byte{1}, []byte{1}), g1Point(nil, nil), scalar), ErrPointNotOnCurve},
+		{"map_top_bytes", MapFpToG1, topBytes[:64], ErrFieldElementTopBytes},
+		{"map_not_canonical", MapFpToG1, g1Point(nil, modulus)[64:], ErrFieldElementNotCanonical},
+		{"msm_not_in_subgroup", G1MSM, pairs(nonSubgroupG1Point(t), scalar), ErrPointNotInSubgroup},
+		{"pairing_not_in_subgroup", Pairing, pairs(nonSubgroupG1Point(t), make([]byte, 256)), ErrPointNotInSubgroup},
+		{"g2_msm_not_in_subgroup", G2MSM, pairs(nonSubgroupG2Point(t), scalar), ErrPointNotInSubgroup},
+		{"pairing_g2_not_in_subgroup", Pairing, pairs(make([]byte, 128), nonSubgroupG2Point(t)), ErrPointNotInSubgroup},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := test.run(test.input); !errors.Is(err, test.err) {
+				t.Fatalf("expected error %v, got %v", test.err, err)
+			}
+		})
+	}
+}
+
 // Benchmarks
 func BenchmarkG1Add(b *testing.B) {
 	benchJson("./test_vectors/blsG1Add.json", G1Add, b)
@@ -276,6 +646,10 @@ func BenchmarkG1MultiExp(b *testing.B) {
 	benchJson("./test_vectors/blsG1MultiExp.json", G1MultiExp, b)
 }
 
+func BenchmarkG1MSM(b *testing.B) {
+	benchJson("./test_vectors/blsG1MultiExp.json", G1MSM, b)
+}
+
 func BenchmarkG2Add(b *testing.B) {
 	benchJson("./test_vectors/blsG2Add.json", G2Add, b)
 }
@@ -288,6 +662,10 @@ func BenchmarkG2MultiExp(b *testing.B) {
 	benchJson("./test_vectors/blsG2MultiExp.json", G2MultiExp, b)
 }
 
+func BenchmarkG2MSM(b *testing.B) {
+	benchJson("./test_vectors/blsG2MultiExp.json", G2MSM, b)
+}
+
 func BenchmarkPairing(b *testing.B) {
 	benchJson("./test_vectors/blsPairing.json", Pairing, b)
 }
@@ -299,3 +677,48 @@ func BenchmarkMapFpToG1(b *testing.B) {
 func BenchmarkMapFp2ToG2(b *testing.B) {
 	benchJson("./test_vectors/blsMapG2.json", MapFp2ToG2, b)
 }
+
+// benchmarkMSMGas reports gas throughput of MSM of k random pairs priced with the final gas schedule.
+func benchmarkMSMGas(b *testing.B, op Operation, run precompileRunner, slice func(r *rand.Rand) []byte) {
+	r := rand.New(rand.NewSource(1))
+	for _, k := range []int{1, 2, 8, 32, 128} {
+		input := []byte{}
+		for i := 0; i < k; i++ {
+			input = append(input, slice(r)...)
+		}
+		gas := RequiredGas(op, input)
+		b.Run(fmt.Sprintf("k=%d-Gas=%d", k, gas), func(b *testing.B) {
+			start := time.Now()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := run(input); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.StopTimer()
+			elapsed := uint64(time.Since(start))
+			if elapsed < 1 {
+				elapsed = 1
+			}
+			b.ReportMetric(float64((100*1000*gas*uint64(b.N))/elapsed)/100, "mgas/s")
+		})
+	}
+}
+
+// MSM checks the subgroup of its points, which multiexponentiation of the draft does not,
+// so the gap between them at the same price is the cost of the check.
+func BenchmarkG1MSMGas(b *testing.B) {
+	benchmarkMSMGas(b, OpG1MSM, G1MSM, concat(randG1, randScalar))
+}
+
+func BenchmarkG1MultiExpGas(b *testing.B) {
+	benchmarkMSMGas(b, OpG1MSM, G1MultiExp, concat(randG1, randScalar))
+}
+
+func BenchmarkG2MSMGas(b *testing.B) {
+	benchmarkMSMGas(b, OpG2MSM, G2MSM, concat(randG2, randScalar))
+}
+
+func BenchmarkG2MultiExpGas(b *testing.B) {
+	benchmarkMSMGas(b, OpG2MSM, G2MultiExp, concat(randG2, randScalar))
+}
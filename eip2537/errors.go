@@ -0,0 +1,22 @@
+package cross_eip2537
+
+import (
+	"errors"
+	"math/big"
+)
+
+// Precompiles reject inputs with these errors, whose messages follow the expected errors of
+// EIP-2537 failure vectors. kilic returns them from its own checks, while blst errors carry no
+// reason and are classified from the input layout in blstRun. ErrInvalidInput wraps blst errors
+// of inputs that break none of the other rules.
+var (
+	ErrInvalidInput             = errors.New("invalid input")
+	ErrInvalidInputLength       = errors.New("invalid input length")
+	ErrFieldElementTopBytes     = errors.New("invalid field element top bytes")
+	ErrFieldElementNotCanonical = errors.New("must be less than modulus")
+	ErrPointNotOnCurve          = errors.New("point is not on curve")
+	ErrPointNotInSubgroup       = errors.New("point is not on correct subgroup")
+)
+
+// fieldModulus is the modulus of the base field.
+var fieldModulus, _ = new(big.Int).SetString("1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab", 16)
@@ -0,0 +1,156 @@
+package cross_eip2537
+
+// Operation is an EIP-2537 operation which gas cost is calculated by a GasSchedule.
+type Operation int
+
+const (
+	OpG1Add Operation = iota
+	OpG1Mul
+	OpG1MSM
+	OpG2Add
+	OpG2Mul
+	OpG2MSM
+	OpPairing
+	OpMapFpToG1
+	OpMapFp2ToG2
+)
+
+// msmDiscountMultiplier divides discounts of MSM discount tables.
+const msmDiscountMultiplier = 1000
+
+// GasSchedule holds gas costs of EIP-2537 operations.
+// MSM of k pairs costs k * Mul * discount(k) / 1000 where discount(k) is the k-th entry of the
+// discount table, or its last entry for larger k. Pairing of k pairs costs PairingBase + k * PairingPerPair.
+type GasSchedule struct {
+	G1Add          uint64
+	G1Mul          uint64
+	G2Add          uint64
+	G2Mul          uint64
+	PairingBase    uint64
+	PairingPerPair uint64
+	MapFpToG1      uint64
+	MapFp2ToG2     uint64
+	G1MSMDiscount  []uint64
+	G2MSMDiscount  []uint64
+}
+
+// finalGasSchedule is the gas schedule of the final specification.
+// Mul is priced as MSM with a single pair.
+var finalGasSchedule = GasSchedule{
+	G1Add:          375,
+	G1Mul:          12000,
+	G2Add:          600,
+	G2Mul:          22500,
+	PairingBase:    37700,
+	PairingPerPair: 32600,
+	MapFpToG1:      5500,
+	MapFp2ToG2:     23800,
+	G1MSMDiscount: []uint64{
+		1000, 949, 848, 797, 764, 750, 738, 728, 719, 712, 705, 698, 692, 687, 682, 677,
+		673, 669, 665, 661, 658, 654, 651, 648, 645, 642, 640, 637, 635, 632, 630, 627,
+		625, 623, 621, 619, 617, 615, 613, 611, 609, 608, 606, 604, 603, 601, 599, 598,
+		596, 595, 593, 592, 591, 589, 588, 586, 585, 584, 582, 581, 580, 579, 577, 576,
+		575, 574, 573, 572, 570, 569, 568, 567, 566, 565, 564, 563, 562, 561, 560, 559,
+		558, 557, 556, 555, 554, 553, 552, 551, 550, 549, 548, 547, 547, 546, 545, 544,
+		543, 542, 541, 540, 540, 539, 538, 537, 536, 536, 535, 534, 533, 532, 532, 531,
+		530, 529, 528, 528, 527, 526, 525, 525, 524, 523, 522, 522, 521, 520, 520, 519,
+	},
+	G2MSMDiscount: []uint64{
+		1000, 1000, 923, 884, 855, 832, 812, 796, 782, 770, 759, 749, 740, 732, 724, 717,
+		711, 704, 699, 693, 688, 683, 679, 674, 670, 666, 663, 659, 655, 652, 649, 646,
+		643, 640, 637, 634, 632, 629, 627, 624, 622, 620, 618, 615, 613, 611, 609, 607,
+		606, 604, 602, 600, 598, 597, 595, 593, 592, 590, 589, 587, 586, 584, 583, 582,
+		580, 579, 578, 576, 575, 574, 573, 571, 570, 569, 568, 567, 566, 565, 563, 562,
+		561, 560, 559, 558, 557, 556, 555, 554, 553, 552, 552, 551, 550, 549, 548, 547,
+		546, 545, 545, 544, 543, 542, 541, 541, 540, 539, 538, 537, 537, 536, 535, 535,
+		534, 533, 532, 532, 531, 530, 530, 529, 528, 528, 527, 526, 526, 525, 524, 524,
+	},
+}
+
+// draftMultiExpDiscount is the discount table shared by G1 and G2 multiexponentiation in the 2020 draft.
+var draftMultiExpDiscount = []uint64{
+	1200, 888, 764, 641, 594, 547, 500, 453, 438, 423, 408, 394, 379, 364, 349, 334,
+	330, 326, 322, 318, 314, 310, 306, 302, 298, 294, 289, 285, 281, 277, 273, 269,
+	268, 266, 265, 263, 262, 260, 259, 257, 256, 254, 253, 251, 250, 248, 247, 245,
+	244, 242, 241, 239, 238, 236, 235, 233, 232, 231, 229, 228, 226, 225, 223, 222,
+	221, 220, 219, 219, 218, 217, 216, 216, 215, 214, 213, 213, 212, 211, 211, 210,
+	209, 208, 208, 207, 206, 205, 205, 204, 203, 202, 202, 201, 200, 199, 199, 198,
+	197, 196, 196, 195, 194, 193, 193, 192, 191, 191, 190, 189, 188, 188, 187, 186,
+	185, 185, 184, 183, 182, 182, 181, 180, 179, 179, 178, 177, 176, 176, 175, 174,
+}
+
+// draftGasSchedule is the gas schedule of the 2020 draft, which test vectors in test_vectors follow.
+var draftGasSchedule = GasSchedule{
+	G1Add:          600,
+	G1Mul:          12000,
+	G2Add:          4500,
+	G2Mul:          55000,
+	PairingBase:    115000,
+	PairingPerPair: 23000,
+	MapFpToG1:      5500,
+	MapFp2ToG2:     110000,
+	G1MSMDiscount:  draftMultiExpDiscount,
+	G2MSMDiscount:  draftMultiExpDiscount,
+}
+
+// FinalGasSchedule returns a copy of the gas schedule of the final specification.
+func FinalGasSchedule() *GasSchedule {
+	return finalGasSchedule.clone()
+}
+
+// DraftGasSchedule returns a copy of the gas schedule of the 2020 draft, which test vectors in test_vectors follow.
+func DraftGasSchedule() *GasSchedule {
+	return draftGasSchedule.clone()
+}
+
+// clone returns a copy of schedule which does not share discount tables with it.
+func (schedule *GasSchedule) clone() *GasSchedule {
+	out := *schedule
+	out.G1MSMDiscount = append([]uint64{}, schedule.G1MSMDiscount...)
+	out.G2MSMDiscount = append([]uint64{}, schedule.G2MSMDiscount...)
+	return &out
+}
+
+// RequiredGas returns the gas cost of operation on input following the final specification.
+func RequiredGas(op Operation, input []byte) uint64 {
+	return finalGasSchedule.RequiredGas(op, input)
+}
+
+// RequiredGas returns the gas cost of operation on input. Inputs are not validated, and
+// malformed input lengths are priced by the number of whole pairs they contain.
+func (schedule *GasSchedule) RequiredGas(op Operation, input []byte) uint64 {
+	switch op {
+	case OpG1Add:
+		return schedule.G1Add
+	case OpG1Mul:
+		return schedule.G1Mul
+	case OpG1MSM:
+		return msmGas(len(input)/160, schedule.G1Mul, schedule.G1MSMDiscount)
+	case OpG2Add:
+		return schedule.G2Add
+	case OpG2Mul:
+		return schedule.G2Mul
+	case OpG2MSM:
+		return msmGas(len(input)/288, schedule.G2Mul, schedule.G2MSMDiscount)
+	case OpPairing:
+		return schedule.PairingBase + uint64(len(input)/384)*schedule.PairingPerPair
+	case OpMapFpToG1:
+		return schedule.MapFpToG1
+	case OpMapFp2ToG2:
+		return schedule.MapFp2ToG2
+	}
+	return 0
+}
+
+func msmGas(k int, mulGas uint64, discountTable []uint64) uint64 {
+	if k == 0 {
+		return 0
+	}
+	var discount uint64
+	if k > len(discountTable) {
+		discount = discountTable[len(discountTable)-1]
+	} else {
+		discount = discountTable[k-1]
+	}
+	return uint64(k) * mulGas * discount / msmDiscountMultiplier
+}
@@ -1,15 +1,5 @@
 package cross_eip2537
 
-import (
-	"errors"
-)
-
-var (
-	errEIP2537InvalidInputLength = errors.New("invalid input length")
-	errEIP2537G1PointSubgroup    = errors.New("g1 point is not on correct subgroup")
-	errEIP2537G2PointSubgroup    = errors.New("g2 point is not on correct subgroup")
-)
-
 const (
 	libBLST  = "blst"
 	libKilic = "kilic"
@@ -17,6 +7,15 @@ const (
 
 var library = libBLST
 
+// backends are the precompiles of each library keyed by library name. Precompiles are keyed by
+// operation name and include the draft operations G1Mul, G1MultiExp, G2Mul and G2MultiExp.
+var backends = map[string]map[string]Precompile{}
+
+// registerBackend registers the precompiles of a library.
+func registerBackend(name string, precompiles map[string]Precompile) {
+	backends[name] = precompiles
+}
+
 var dst = []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_")
 
 func SetDST(_dst []byte) {